@@ -1,6 +1,10 @@
 package v1beta1
 
 import (
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -8,13 +12,49 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
-// +kubebuilder:validation:Enum=DISABLED;SOFT;HARD
+// +kubebuilder:validation:Enum=DISABLED;SOFT;HARD;VERSIONS
 type CleanupPolicy string
 
 const (
 	DISABLED CleanupPolicy = "DISABLED"
 	SOFT     CleanupPolicy = "SOFT"
 	HARD     CleanupPolicy = "HARD"
+	VERSIONS CleanupPolicy = "VERSIONS"
+)
+
+// +kubebuilder:validation:Enum=Block;SkipAndRelease;Force
+type CleanupOnReferenced string
+
+const (
+	ReferencedBlock          CleanupOnReferenced = "Block"
+	ReferencedSkipAndRelease CleanupOnReferenced = "SkipAndRelease"
+	ReferencedForce          CleanupOnReferenced = "Force"
+)
+
+// +kubebuilder:validation:Enum=Report;Correct;Ignore
+type DriftPolicy string
+
+const (
+	DriftReport  DriftPolicy = "Report"
+	DriftCorrect DriftPolicy = "Correct"
+	DriftIgnore  DriftPolicy = "Ignore"
+)
+
+// +kubebuilder:validation:Enum=Fail;AdoptIfMatches;AdoptAndUpdate
+type AdoptionMode string
+
+const (
+	AdoptionFail   AdoptionMode = "Fail"
+	AdoptIfMatches AdoptionMode = "AdoptIfMatches"
+	AdoptAndUpdate AdoptionMode = "AdoptAndUpdate"
+)
+
+// +kubebuilder:validation:Enum=None;DeleteNewerVersions
+type RollbackPolicy string
+
+const (
+	RollbackNone                RollbackPolicy = "None"
+	RollbackDeleteNewerVersions RollbackPolicy = "DeleteNewerVersions"
 )
 
 // +kubebuilder:validation:Enum=io.confluent.kafka.serializers.subject.TopicNameStrategy;io.confluent.kafka.serializers.subject.RecordNameStrategy;io.confluent.kafka.serializers.subject.TopicRecordNameStrategy
@@ -35,6 +75,13 @@ const (
 	PROTOBUF SchemaFormat = "PROTOBUF"
 )
 
+// +kubebuilder:validation:Enum=AVRO_IDL
+type SourceFormat string
+
+const (
+	AVRO_IDL SourceFormat = "AVRO_IDL"
+)
+
 // +kubebuilder:validation:Enum=NONE;BACKWARD;BACKWARD_TRANSITIVE;FORWARD;FORWARD_TRANSITIVE;FULL;FULL_TRANSITIVE
 type CompatibilityMode string
 
@@ -48,11 +95,38 @@ const (
 	FULL_TRANSITIVE     CompatibilityMode = "FULL_TRANSITIVE"
 )
 
+// +kubebuilder:validation:XValidation:rule="[has(self.schema), has(self.schemaFrom), has(self.schemaObject)].filter(x, x).size() == 1",message="exactly one of schema, schemaFrom and schemaObject is required"
+// +kubebuilder:validation:XValidation:rule="!has(self.sourceFormat) || self.format == 'AVRO'",message="AVRO_IDL source format requires AVRO format"
+// +kubebuilder:validation:XValidation:rule="!has(self.importsFrom) || has(self.sourceFormat)",message="importsFrom requires sourceFormat"
 type KafkaSchemaData struct {
 	// Schema payload. Format depends on associated "format" field
-	Schema string `json:"schema"`
+	Schema string `json:"schema,omitempty"`
+	/*
+		SchemaObject defines schema payload (e.g. AVRO or JSON schema) as structured YAML instead of JSON string,
+		so that individual fields can be patched (e.g. by Helm or Kustomize).
+		It's serialized to JSON deterministically (object keys sorted) before normalization and registration
+	*/
+	// +kubebuilder:pruning:PreserveUnknownFields
+	SchemaObject *apiextensionsv1.JSON `json:"schemaObject,omitempty"`
+	/*
+		SchemaFrom loads schema payload from ConfigMap or Secret (in namespace of the resource)
+		instead of inline schema, e.g. for large schemas generated from files.
+		Changes of referenced ConfigMaps and Secrets are reconciled as well
+	*/
+	SchemaFrom *SchemaSource `json:"schemaFrom,omitempty"`
 	// Format of the provided schema
 	Format SchemaFormat `json:"format"`
+	/*
+		SourceFormat the schema is authored in, if it's compiled to "format" before registration.
+		AVRO_IDL (Avro IDL compiled to AVRO) is supported
+	*/
+	SourceFormat SourceFormat `json:"sourceFormat,omitempty"`
+	/*
+		ImportsFrom lists ConfigMaps (in namespace of the resource) holding files imported by Avro IDL,
+		keyed by import path or file name (ConfigMap keys can't contain "/").
+		ConfigMap the schema is loaded from (if any) is searched as well
+	*/
+	ImportsFrom []corev1.LocalObjectReference `json:"importsFrom,omitempty"`
 	/*
 		Compatibility defines schema compatibility mode for the subject.
 		If not provided, subject will inherit default compatibility mode defined in schema registry
@@ -69,6 +143,212 @@ type KafkaSchemaData struct {
 		Currently supported only for AVRO. Otherwise, it's ignored
 	*/
 	Normalize bool `json:"normalize,omitempty"`
+
+	/*
+		Metadata (properties, tags and sensitive properties) registered along with the schema.
+		Changing metadata registers new version of the schema
+	*/
+	Metadata *Metadata `json:"metadata,omitempty"`
+	/*
+		MetadataFromResource adds properties describing the resource to schema metadata.
+		Properties explicitly defined in .metadata take precedence
+	*/
+	MetadataFromResource *MetadataFromResource `json:"metadataFromResource,omitempty"`
+	/*
+		RuleSet (domain and migration rules) registered along with the schema.
+		CEL expressions are validated by the operator before registration.
+		Changing rule set registers new version of the schema
+	*/
+	RuleSet *RuleSet `json:"ruleSet,omitempty"`
+	/*
+		References of schemas registered under other subjects the schema depends on
+		(e.g. Avro named types or Protobuf imports registered independently).
+		They take precedence over references of Protobuf imports registered by the operator with the same name
+	*/
+	References []SchemaReference `json:"references,omitempty"`
+	/*
+		Id pins id of the registered schema (e.g. when rebuilding schema registry after a disaster).
+		Schema with pinned id (or version) is registered in IMPORT mode - subject is temporarily switched to it
+	*/
+	// +kubebuilder:validation:Minimum=1
+	Id int `json:"id,omitempty"`
+	/*
+		Version pins version of the registered schema
+	*/
+	// +kubebuilder:validation:Minimum=1
+	Version int `json:"version,omitempty"`
+}
+
+// SchemaReference references schema registered under another subject
+type SchemaReference struct {
+	/*
+		Name the schema refers to the referenced one by:
+		fully qualified name of Avro type, import path of Protobuf file or URL of JSON schema
+	*/
+	Name string `json:"name"`
+	// Subject the referenced schema is registered under
+	Subject string `json:"subject"`
+	// Version of the referenced schema
+	// +kubebuilder:validation:Minimum=1
+	Version int `json:"version"`
+}
+
+/*
+SchemaSource selects schema payload stored outside the resource.
+Exactly one of configMapKeyRef, secretKeyRef and protobufFiles has to be provided
+*/
+// +kubebuilder:validation:XValidation:rule="[has(self.configMapKeyRef), has(self.secretKeyRef), has(self.protobufFiles)].filter(x, x).size() == 1",message="exactly one of configMapKeyRef, secretKeyRef and protobufFiles is required"
+type SchemaSource struct {
+	// ConfigMapKeyRef selects key of ConfigMap holding schema payload
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// SecretKeyRef selects key of Secret holding schema payload
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// ProtobufFiles loads multi-file Protobuf schema (PROTOBUF format only) from ConfigMap holding .proto files
+	ProtobufFiles *ProtobufFiles `json:"protobufFiles,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=FilePath;FileName
+type ImportSubjectNaming string
+
+const (
+	ImportByFilePath ImportSubjectNaming = "FilePath"
+	ImportByFileName ImportSubjectNaming = "FileName"
+)
+
+/*
+ProtobufFiles is a directory of .proto files stored in ConfigMap (one file per key).
+Files imported (directly or transitively) by the entrypoint are registered under their own subjects
+and referenced by the schema. Import cycles are rejected
+*/
+type ProtobufFiles struct {
+	// ConfigMapName is the name of ConfigMap (in namespace of the resource) holding .proto files
+	ConfigMapName string `json:"configMapName"`
+	// Entrypoint is the key of the main .proto file, registered as the schema of the resource
+	Entrypoint string `json:"entrypoint"`
+	/*
+		Paths maps ConfigMap keys to import paths of the files (e.g. "common/money.proto"),
+		since ConfigMap keys can't contain "/". Files not listed here are imported by their keys
+	*/
+	Paths map[string]string `json:"paths,omitempty"`
+	/*
+		SubjectNaming defines subjects imported files are registered under:
+		FilePath - import path of the file, e.g. "common/money.proto" (default)
+		FileName - file name without directories, e.g. "money.proto"
+	*/
+	SubjectNaming ImportSubjectNaming `json:"subjectNaming,omitempty"`
+	// SubjectPrefix is prepended to subjects of imported files
+	SubjectPrefix string `json:"subjectPrefix,omitempty"`
+}
+
+/*
+SchemaVersion is historical version of the schema, in format of .data.format.
+Exactly one of schema and configMapKeyRef has to be provided
+*/
+type SchemaVersion struct {
+	// Schema payload
+	Schema string `json:"schema,omitempty"`
+	// ConfigMapKeyRef selects key of ConfigMap (in namespace of the resource) holding schema payload
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+/*
+MetadataFromResource defines which properties of the resource are propagated to schema metadata:
+k8s.namespace and k8s.name are always added, followed by listed labels and annotations (keyed by their names)
+*/
+type MetadataFromResource struct {
+	Labels      []string `json:"labels,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=READWRITE;READONLY;READONLY_OVERRIDE;IMPORT
+type SubjectMode string
+
+const (
+	ModeReadWrite        SubjectMode = "READWRITE"
+	ModeReadOnly         SubjectMode = "READONLY"
+	ModeReadOnlyOverride SubjectMode = "READONLY_OVERRIDE"
+	ModeImport           SubjectMode = "IMPORT"
+)
+
+// +kubebuilder:validation:Enum=TRANSFORM;CONDITION
+type RuleKind string
+
+const (
+	RuleTransform RuleKind = "TRANSFORM"
+	RuleCondition RuleKind = "CONDITION"
+)
+
+// +kubebuilder:validation:Enum=UPGRADE;DOWNGRADE;UPDOWN;WRITE;READ;WRITEREAD
+type RuleMode string
+
+const (
+	// migration rule modes
+	RuleUpgrade   RuleMode = "UPGRADE"
+	RuleDowngrade RuleMode = "DOWNGRADE"
+	RuleUpDown    RuleMode = "UPDOWN"
+	// domain rule modes
+	RuleWrite     RuleMode = "WRITE"
+	RuleRead      RuleMode = "READ"
+	RuleWriteRead RuleMode = "WRITEREAD"
+)
+
+/*
+Metadata of the schema (see Data Contracts in Confluent documentation)
+*/
+type Metadata struct {
+	// Tags maps paths of schema elements (e.g. "**.ssn") to tags
+	Tags map[string][]string `json:"tags,omitempty"`
+	// Properties are arbitrary key-value properties of the schema
+	Properties map[string]string `json:"properties,omitempty"`
+	// Sensitive lists properties which shouldn't be exposed
+	Sensitive []string `json:"sensitive,omitempty"`
+}
+
+/*
+Rule of the schema (see Data Contracts in Confluent documentation)
+*/
+type Rule struct {
+	Name string   `json:"name"`
+	Doc  string   `json:"doc,omitempty"`
+	Kind RuleKind `json:"kind"`
+	Mode RuleMode `json:"mode"`
+	// Type of the rule executor (e.g. CEL, CEL_FIELD, JSONATA)
+	Type      string            `json:"type"`
+	Tags      []string          `json:"tags,omitempty"`
+	Params    map[string]string `json:"params,omitempty"`
+	Expr      string            `json:"expr,omitempty"`
+	OnSuccess string            `json:"onSuccess,omitempty"`
+	OnFailure string            `json:"onFailure,omitempty"`
+	Disabled  bool              `json:"disabled,omitempty"`
+}
+
+/*
+RuleSet of the schema (see Data Contracts in Confluent documentation)
+*/
+type RuleSet struct {
+	MigrationRules []Rule `json:"migrationRules,omitempty"`
+	DomainRules    []Rule `json:"domainRules,omitempty"`
+}
+
+/*
+SubjectConfig defines subject level config of schema registry (besides compatibility,
+which is defined by .data.compatibility). Settings which aren't provided are inherited from global config
+*/
+type SubjectConfig struct {
+	// Normalize makes schema registry normalize schemas registered under the subject
+	Normalize *bool `json:"normalize,omitempty"`
+	// CompatibilityGroup is the name of metadata property whose value splits versions into compatibility groups
+	CompatibilityGroup string `json:"compatibilityGroup,omitempty"`
+	// Alias makes the subject an alias of another subject
+	Alias string `json:"alias,omitempty"`
+	// DefaultMetadata is applied to registered schemas which don't define metadata
+	DefaultMetadata *Metadata `json:"defaultMetadata,omitempty"`
+	// OverrideMetadata is applied to all registered schemas
+	OverrideMetadata *Metadata `json:"overrideMetadata,omitempty"`
+	// DefaultRuleSet is applied to registered schemas which don't define rule set
+	DefaultRuleSet *RuleSet `json:"defaultRuleSet,omitempty"`
+	// OverrideRuleSet is applied to all registered schemas
+	OverrideRuleSet *RuleSet `json:"overrideRuleSet,omitempty"`
 }
 
 type SchemaRegistry struct {
@@ -77,6 +357,13 @@ type SchemaRegistry struct {
 		If not provided, controller will fall back to default configuration
 	*/
 	BaseUrl string `json:"baseUrl,omitempty"`
+	/*
+		Context is the schema registry context (e.g. ".team-a") subjects are qualified with (":.team-a:subject"),
+		"." stands for the default context.
+		If not provided, controller will fall back to its default (configurable per namespace) context
+	*/
+	// +kubebuilder:validation:Pattern=`^\.?[a-zA-Z0-9_.-]*$`
+	Context string `json:"context,omitempty"`
 }
 
 // KafkaSchemaSpec defines the desired state of KafkaSchema
@@ -103,10 +390,99 @@ type KafkaSchemaSpec struct {
 		DISABLED: no effect - controller won't attend to remove schemas and subjects
 		SOFT: soft deletion - controller will delete subjects but leave schemas untouched
 		HARD: hard deletion - controller will delete subjects and referenced schemas. NOTE: if schema is referenced by another subject, schema registry won't effectively delete it
+		VERSIONS: versions deletion - controller will (soft-)delete only subject versions registered by this resource
+			(see status.registeredVersions), leaving other versions and the subject untouched
 
 		If not provided, controller will fall back to its default (configurable) behaviour
 	*/
 	CleanupPolicy CleanupPolicy `json:"cleanupPolicy,omitempty"`
+	// PermanentVersionsCleanup makes VERSIONS cleanup policy permanently delete versions after soft-deleting them
+	PermanentVersionsCleanup bool `json:"permanentVersionsCleanup,omitempty"`
+	/*
+		DeletionProtection makes validating webhook reject deletion of the resource (and switching its CleanupPolicy to HARD)
+		until it's annotated with "kafka.incubly.oss/unlock-deletion: true".
+
+		If not provided, controller will fall back to its default (configurable per namespace label) behaviour
+	*/
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
+	/*
+		CleanupOnReferenced defines what controller does on cleanup if versions about to be deleted
+		are referenced by schemas of other subjects:
+		Block: controller keeps the finalizer (and the resource) and sets "ReferencedBy" condition listing referencing subjects
+		SkipAndRelease: controller skips schema registry cleanup and releases the finalizer
+		Force: controller performs cleanup regardless of references (schema registry may still refuse it)
+
+		If not provided, defaults to Block
+	*/
+	CleanupOnReferenced CleanupOnReferenced `json:"cleanupOnReferenced,omitempty"`
+	/*
+		DriftPolicy defines how controller reacts to changes made in schema registry outside of the operator
+		(e.g. newer schema version registered manually, compatibility mode changed via REST API, subject soft-deleted):
+		Report: controller sets "Drifted" condition with drift details, but doesn't modify schema registry
+		Correct: controller sets "Drifted" condition and re-applies the resource on schema registry
+		Ignore: controller doesn't check schema registry for drift
+
+		Drift is only checked for resources that were already successfully reconciled in their current generation.
+		If not provided, controller will fall back to its default (configurable) behaviour
+	*/
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	/*
+		Adoption defines what controller does when resource is reconciled for the first time
+		and its subject already exists in schema registry (e.g. it was registered manually):
+		Fail: controller won't touch the subject and reconciliation fails
+		AdoptIfMatches: controller adopts the subject only if its latest version and compatibility mode match the resource
+		AdoptAndUpdate: controller adopts the subject and registers schema from the resource (possibly as a new version)
+
+		Adopted subject version is recorded in status and in "kafka.incubly.oss/adopted-version" annotation.
+		If not provided, controller will fall back to its default (configurable) behaviour
+	*/
+	Adoption AdoptionMode `json:"adoption,omitempty"`
+	/*
+		RestoreSoftDeleted makes controller reinstate soft-deleted subject when resource is reconciled for the first time
+		(e.g. re-created after deletion with SOFT cleanup policy): previous versions are re-registered in order
+		before registering the schema, so schema ids stay continuous. Restored subject is adopted by the resource.
+	*/
+	RestoreSoftDeleted bool `json:"restoreSoftDeleted,omitempty"`
+	/*
+		SubjectConfig defines subject level config of schema registry.
+		Operator converges it to the resource, removing settings which aren't defined anymore
+	*/
+	SubjectConfig *SubjectConfig `json:"subjectConfig,omitempty"`
+	/*
+		SubjectMode defines subject level mode of schema registry: READWRITE, READONLY (or READONLY_OVERRIDE)
+		freezing the subject, or IMPORT allowing registration of schemas with pinned ids.
+		If not provided, subject level mode is left as it is (including the mode set outside the operator),
+		READWRITE has to be set explicitly to unfreeze the subject
+	*/
+	SubjectMode SubjectMode `json:"subjectMode,omitempty"`
+	/*
+		RollbackPolicy defines what happens when the schema matches older version of the subject
+		(e.g. after reverting a bad deployment), which schema registry doesn't make the latest one:
+		None - leave the subject as it is (default)
+		DeleteNewerVersions - soft-delete newer versions, so the matching one becomes the latest.
+		Rollback has to be approved by annotating the resource with
+		kafka.incubly.oss/approve-rollback set to the version rolled back to
+	*/
+	RollbackPolicy RollbackPolicy `json:"rollbackPolicy,omitempty"`
+	/*
+		CleanupDelay postpones schema registry cleanup after resource deletion.
+		Scheduled cleanup time is recorded in status and resource is released only after the delay elapses
+		and cleanup succeeds. Ignored for DISABLED cleanup policy.
+	*/
+	CleanupDelay *metav1.Duration `json:"cleanupDelay,omitempty"`
+	/*
+		Ttl makes controller delete the resource (with regular cleanup) once it outlives given duration
+		counted from its creation. Intended for short-lived (e.g. test) environments.
+		Resources protected from deletion (see DeletionProtection) are kept until unlocked,
+		condition with type="TtlExpired" reports it
+	*/
+	Ttl *metav1.Duration `json:"ttl,omitempty"`
+	/*
+		Versions is ordered history of the schema (oldest first) preceding .data.schema.
+		Versions missing in the subject are replayed in order before .data.schema is registered,
+		so every environment ends up with the same evolution history (version numbers and compatibility baseline)
+	*/
+	Versions []SchemaVersion `json:"versions,omitempty"`
 	/*
 		SchemaRegistry optionally overrides controller default reference to schema registry it targets
 	*/
@@ -122,9 +498,14 @@ type KafkaSchemaSpec struct {
 // KafkaSchemaStatus defines the observed state of KafkaSchema
 type KafkaSchemaStatus struct {
 	// Represents observations of the current state of KafkaSchema.
-	// Operator uses single condition with type="Ready" and statuses:
+	// Operator uses condition with type="Ready" and statuses:
 	// True (reconciliation complete), False (reconciliation failed)
 	// and Unknown (reconciliation in progress).
+	// Additionally, condition with type="Drifted" reflects differences
+	// between the resource and schema registry state (see DriftPolicy)
+	// and condition with type="ReferencedBy" reflects cleanup blocked by references (see CleanupOnReferenced).
+	// Condition with type="Paused" is set while reconciliation is paused with "kafka.incubly.oss/paused" annotation.
+	// Condition with type="TtlExpired" is set while expired resource is kept due to its deletion protection.
 	//
 	// +listType=map
 	// +listMapKey=type
@@ -136,6 +517,27 @@ type KafkaSchemaStatus struct {
 	SchemaRegistryUrl string `json:"schemaRegistryUrl,omitempty"`
 	// SchemaId is the identifier of the schema in the schema registry
 	SchemaId int `json:"keySchemaId,omitempty"`
+	// SchemaVersion is the subject version under which the schema is registered
+	SchemaVersion int `json:"schemaVersion,omitempty"`
+	/*
+		LatestVersion is the latest version of the subject when the schema was registered.
+		It's newer than SchemaVersion if older schema was re-applied without rollback
+	*/
+	LatestVersion int `json:"latestVersion,omitempty"`
+	// RegisteredVersions are subject versions created by this resource (deleted by VERSIONS cleanup policy)
+	RegisteredVersions []int `json:"registeredVersions,omitempty"`
+	// Adoption describes pre-existing subject adopted by this resource (if any)
+	Adoption *Adoption `json:"adoption,omitempty"`
+	// SubjectCreated tells if the subject didn't exist before this resource registered its first schema
+	// (only subjects created by the operator are subject to garbage collection of orphaned subjects)
+	SubjectCreated bool `json:"subjectCreated,omitempty"`
+	// ObservedGeneration is the most recent generation of the resource that was successfully reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// SchemaContentHash is SHA-256 hash of schema payload (inline or loaded from .data.schemaFrom) last reconciled,
+	// including its references (e.g. files imported by multi-file Protobuf schema)
+	SchemaContentHash string `json:"schemaContentHash,omitempty"`
+	// ScheduledCleanupTime is the time schema registry cleanup of deleted resource is postponed until
+	ScheduledCleanupTime *metav1.Time `json:"scheduledCleanupTime,omitempty"`
 	// Subject is the schema registry subject (based on NamingStrategy)
 	Subject string `json:"subject,omitempty"`
 	// Healthy boolean reflects current health of the resource
@@ -148,6 +550,18 @@ type KafkaSchemaStatus struct {
 	LastRetryTsEpoch int64 `json:"lastRetryTsEpoch,omitempty"`
 }
 
+// Adoption describes state of pre-existing subject at the moment it was adopted by KafkaSchema
+type Adoption struct {
+	// Version is the latest subject version at the moment of adoption
+	Version int `json:"version"`
+	// SchemaId is the identifier of the latest subject schema at the moment of adoption
+	SchemaId int `json:"schemaId"`
+	// Compatibility is the subject-level compatibility mode at the moment of adoption (if set)
+	Compatibility CompatibilityMode `json:"compatibility,omitempty"`
+	// AdoptedAt is the time of adoption
+	AdoptedAt metav1.Time `json:"adoptedAt"`
+}
+
 type ReadyReason struct {
 	Name   string
 	Status metav1.ConditionStatus
@@ -162,7 +576,53 @@ var (
 	RegisterSchema       = ReadyReason{"RegisterSchema", metav1.ConditionFalse}
 	ResourceUpdate       = ReadyReason{"ResourceUpdate", metav1.ConditionFalse}
 	SetCompatibilityMode = ReadyReason{"SetCompatibilityMode", metav1.ConditionFalse}
+	DetectDrift          = ReadyReason{"DetectDrift", metav1.ConditionFalse}
+	Adopt                = ReadyReason{"Adopt", metav1.ConditionFalse}
+	Restore              = ReadyReason{"Restore", metav1.ConditionFalse}
 	Cleanup              = ReadyReason{"Cleanup", metav1.ConditionFalse}
+	ValidateRuleSet      = ReadyReason{"ValidateRuleSet", metav1.ConditionFalse}
+	SetSubjectMode       = ReadyReason{"SetSubjectMode", metav1.ConditionFalse}
+	ReadOnlySubject      = ReadyReason{"ReadOnlySubject", metav1.ConditionFalse}
+	PinnedId             = ReadyReason{"PinnedId", metav1.ConditionFalse}
+	ReplayVersions       = ReadyReason{"ReplayVersions", metav1.ConditionFalse}
+	ResolveSchema        = ReadyReason{"ResolveSchema", metav1.ConditionFalse}
+	CompileSchema        = ReadyReason{"CompileSchema", metav1.ConditionFalse}
+	RegisterImports      = ReadyReason{"RegisterImports", metav1.ConditionFalse}
+	Rollback             = ReadyReason{"Rollback", metav1.ConditionFalse}
+	RollbackPending      = ReadyReason{"RollbackPending", metav1.ConditionFalse}
+	CleanupScheduled     = ReadyReason{"CleanupScheduled", metav1.ConditionUnknown}
+)
+
+// Reasons of the "Paused" condition
+var (
+	Paused = ReadyReason{"Paused", metav1.ConditionTrue}
+)
+
+// Reasons of the "ReadOnly" condition
+var (
+	RegistrationBlocked = ReadyReason{"RegistrationBlocked", metav1.ConditionTrue}
+)
+
+// Reasons of the "IdMismatch" condition
+var (
+	IdConflict = ReadyReason{"IdConflict", metav1.ConditionTrue}
+)
+
+// Reasons of the "ReferencedBy" condition
+var (
+	Referenced = ReadyReason{"Referenced", metav1.ConditionTrue}
+)
+
+// Reasons of the "TtlExpired" condition
+var (
+	DeletionProtected = ReadyReason{"DeletionProtected", metav1.ConditionTrue}
+)
+
+// Reasons of the "Drifted" condition
+var (
+	InSync         = ReadyReason{"InSync", metav1.ConditionFalse}
+	DriftDetected  = ReadyReason{"DriftDetected", metav1.ConditionTrue}
+	DriftCorrected = ReadyReason{"DriftCorrected", metav1.ConditionFalse}
 )
 
 //+kubebuilder:object:root=true
@@ -178,12 +638,46 @@ type KafkaSchema struct {
 }
 
 func (in *KafkaSchema) SetReadyReason(reason ReadyReason, msg string) bool {
+	return in.SetCondition("Ready", reason, msg)
+}
+
+func (in *KafkaSchema) SetDriftedReason(reason ReadyReason, msg string) bool {
+	return in.SetCondition("Drifted", reason, msg)
+}
+
+func (in *KafkaSchema) SetCondition(conditionType string, reason ReadyReason, msg string) bool {
+	return setCondition(&in.Status.Conditions, in.Generation, conditionType, reason, msg)
+}
+
+func (in *KafkaSchema) RemoveCondition(conditionType string) bool {
+	return meta.RemoveStatusCondition(&in.Status.Conditions, conditionType)
+}
+
+// EffectiveCleanupPolicy returns CleanupPolicy of the resource, falling back to operator default
+// (DEFAULT_CLEANUP_POLICY env var) and DISABLED
+func (in *KafkaSchema) EffectiveCleanupPolicy() CleanupPolicy {
+	if len(in.Spec.CleanupPolicy) > 0 {
+		return in.Spec.CleanupPolicy
+	}
+	defaultCleanupPolicy := os.Getenv("DEFAULT_CLEANUP_POLICY")
+	if len(defaultCleanupPolicy) > 1 {
+		return CleanupPolicy(defaultCleanupPolicy)
+	}
+	return DISABLED
+}
+
+func setCondition(
+	conditions *[]metav1.Condition,
+	generation int64,
+	conditionType string,
+	reason ReadyReason,
+	msg string) bool {
 	return meta.SetStatusCondition(
-		&in.Status.Conditions,
+		conditions,
 		metav1.Condition{
-			Type:               "Ready",
+			Type:               conditionType,
 			Status:             reason.Status,
-			ObservedGeneration: in.Generation,
+			ObservedGeneration: generation,
 			Reason:             reason.Name,
 			Message:            msg,
 		},
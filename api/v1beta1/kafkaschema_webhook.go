@@ -0,0 +1,134 @@
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var kafkaschemalog = logf.Log.WithName("kafkaschema-resource")
+
+// UnlockDeletionAnnotation (set to "true") lifts DeletionProtection of the resource
+const UnlockDeletionAnnotation = "kafka.incubly.oss/unlock-deletion"
+
+// SetupWebhookWithManager registers validating webhook enforcing DeletionProtection
+func (r *KafkaSchema) SetupWebhookWithManager(mgr ctrl.Manager, validator *KafkaSchemaValidator) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(validator).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-kafka-incubly-oss-v1beta1-kafkaschema,mutating=false,failurePolicy=fail,sideEffects=None,groups=kafka.incubly.oss,resources=kafkaschemas,verbs=update;delete,versions=v1beta1,name=vkafkaschema.kb.io,admissionReviewVersions=v1
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// KafkaSchemaValidator rejects deletion of protected KafkaSchema resources
+// and switching their CleanupPolicy to HARD, unless they were unlocked first
+// +kubebuilder:object:generate=false
+type KafkaSchemaValidator struct {
+	client.Reader
+	// ProtectedNamespaces selects namespaces whose resources are protected by default (if not nil)
+	ProtectedNamespaces labels.Selector
+}
+
+var _ admission.CustomValidator = &KafkaSchemaValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *KafkaSchemaValidator) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *KafkaSchemaValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldSchema, ok := oldObj.(*KafkaSchema)
+	if !ok {
+		return nil, fmt.Errorf("expected KafkaSchema, got %T", oldObj)
+	}
+	newSchema, ok := newObj.(*KafkaSchema)
+	if !ok {
+		return nil, fmt.Errorf("expected KafkaSchema, got %T", newObj)
+	}
+	if lifted, err := v.liftsProtection(ctx, oldSchema, newSchema); err != nil {
+		return nil, err
+	} else if lifted {
+		kafkaschemalog.Info("validate lifting deletion protection", "name", newSchema.Name)
+		if err := v.validateUnlocked(ctx, oldSchema, "lifting deletion protection"); err != nil {
+			return nil, err
+		}
+	}
+	// clearing the policy falls back to operator default, which may be HARD as well
+	if newSchema.EffectiveCleanupPolicy() != HARD || oldSchema.EffectiveCleanupPolicy() == HARD {
+		return nil, nil
+	}
+	kafkaschemalog.Info("validate switching cleanup policy to HARD", "name", newSchema.Name)
+	return nil, v.validateUnlocked(ctx, oldSchema, "switching cleanup policy to HARD")
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *KafkaSchemaValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	schema, ok := obj.(*KafkaSchema)
+	if !ok {
+		return nil, fmt.Errorf("expected KafkaSchema, got %T", obj)
+	}
+	kafkaschemalog.Info("validate delete", "name", schema.Name)
+	return nil, v.validateUnlocked(ctx, schema, "deletion")
+}
+
+// Protects tells if deletion of the resource is rejected (it's protected and wasn't unlocked)
+func (v *KafkaSchemaValidator) Protects(ctx context.Context, schema *KafkaSchema) (bool, error) {
+	if schema.Annotations[UnlockDeletionAnnotation] == "true" {
+		return false, nil
+	}
+	return v.isProtected(ctx, schema)
+}
+
+func (v *KafkaSchemaValidator) validateUnlocked(ctx context.Context, schema *KafkaSchema, operation string) error {
+	protected, err := v.Protects(ctx, schema)
+	if err != nil {
+		return err
+	}
+	if protected {
+		return fmt.Errorf("KafkaSchema %s/%s is protected from %s, annotate it with %s=true first",
+			schema.Namespace, schema.Name, operation, UnlockDeletionAnnotation)
+	}
+	return nil
+}
+
+/*
+liftsProtection tells if update of protected resource leaves it unprotected
+(deletionProtection switched to false, or cleared in namespace protected by default)
+*/
+func (v *KafkaSchemaValidator) liftsProtection(ctx context.Context, oldSchema, newSchema *KafkaSchema) (bool, error) {
+	if equality.Semantic.DeepEqual(oldSchema.Spec.DeletionProtection, newSchema.Spec.DeletionProtection) {
+		return false, nil
+	}
+	if protected, err := v.isProtected(ctx, oldSchema); err != nil || !protected {
+		return false, err
+	}
+	protected, err := v.isProtected(ctx, newSchema)
+	return !protected, err
+}
+
+func (v *KafkaSchemaValidator) isProtected(ctx context.Context, schema *KafkaSchema) (bool, error) {
+	if schema.Spec.DeletionProtection != nil {
+		return *schema.Spec.DeletionProtection, nil
+	}
+	if v.ProtectedNamespaces == nil || v.ProtectedNamespaces.Empty() {
+		return false, nil
+	}
+	namespace := &corev1.Namespace{}
+	if err := v.Get(ctx, types.NamespacedName{Name: schema.Namespace}, namespace); err != nil {
+		return false, err
+	}
+	return v.ProtectedNamespaces.Matches(labels.Set(namespace.Labels)), nil
+}
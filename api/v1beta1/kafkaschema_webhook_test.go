@@ -0,0 +1,139 @@
+package v1beta1
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("KafkaSchema Webhook", func() {
+
+	ctx := context.Background()
+	validator := &KafkaSchemaValidator{
+		Reader:              namespaceReader{"prod": {"env": "prod"}, "dev": {"env": "dev"}},
+		ProtectedNamespaces: labels.SelectorFromSet(labels.Set{"env": "prod"}),
+	}
+
+	Context("Deletion protection", func() {
+		It("Should reject deletion of protected resource", func() {
+			_, err := validator.ValidateDelete(ctx, aProtectedSchema("dev", ptr(true)))
+			Expect(err).Should(HaveOccurred())
+		})
+		It("Should allow deletion of protected resource once unlocked", func() {
+			schema := aProtectedSchema("dev", ptr(true))
+			schema.Annotations = map[string]string{UnlockDeletionAnnotation: "true"}
+			_, err := validator.ValidateDelete(ctx, schema)
+			Expect(err).Should(Succeed())
+		})
+		It("Should protect resources in matching namespaces by default", func() {
+			_, err := validator.ValidateDelete(ctx, aProtectedSchema("prod", nil))
+			Expect(err).Should(HaveOccurred())
+
+			_, err = validator.ValidateDelete(ctx, aProtectedSchema("dev", nil))
+			Expect(err).Should(Succeed())
+		})
+		It("Should let resource opt out of namespace default", func() {
+			_, err := validator.ValidateDelete(ctx, aProtectedSchema("prod", ptr(false)))
+			Expect(err).Should(Succeed())
+		})
+		It("Should reject switching cleanup policy of protected resource to HARD", func() {
+			oldSchema := aProtectedSchema("prod", nil)
+			newSchema := oldSchema.DeepCopy()
+			newSchema.Spec.CleanupPolicy = HARD
+
+			_, err := validator.ValidateUpdate(ctx, oldSchema, newSchema)
+			Expect(err).Should(HaveOccurred())
+
+			By("And allow it once unlocked")
+			oldSchema.Annotations = map[string]string{UnlockDeletionAnnotation: "true"}
+			_, err = validator.ValidateUpdate(ctx, oldSchema, newSchema)
+			Expect(err).Should(Succeed())
+		})
+		It("Should reject clearing cleanup policy of protected resource if default policy is HARD", func() {
+			Expect(os.Setenv("DEFAULT_CLEANUP_POLICY", string(HARD))).To(Succeed())
+			DeferCleanup(os.Unsetenv, "DEFAULT_CLEANUP_POLICY")
+			oldSchema := aProtectedSchema("prod", nil)
+			newSchema := oldSchema.DeepCopy()
+			newSchema.Spec.CleanupPolicy = ""
+
+			_, err := validator.ValidateUpdate(ctx, oldSchema, newSchema)
+			Expect(err).Should(HaveOccurred())
+
+			By("And allow setting HARD explicitly if it's the default already")
+			oldSchema.Spec.CleanupPolicy = ""
+			newSchema.Spec.CleanupPolicy = HARD
+			_, err = validator.ValidateUpdate(ctx, oldSchema, newSchema)
+			Expect(err).Should(Succeed())
+		})
+		It("Should reject lifting deletion protection of protected resource", func() {
+			oldSchema := aProtectedSchema("dev", ptr(true))
+			newSchema := oldSchema.DeepCopy()
+			newSchema.Spec.DeletionProtection = ptr(false)
+
+			_, err := validator.ValidateUpdate(ctx, oldSchema, newSchema)
+			Expect(err).Should(HaveOccurred())
+
+			By("And reject opting out of namespace default")
+			oldSchema = aProtectedSchema("prod", nil)
+			_, err = validator.ValidateUpdate(ctx, oldSchema, newSchema)
+			Expect(err).Should(HaveOccurred())
+
+			By("And allow it once unlocked")
+			oldSchema.Annotations = map[string]string{UnlockDeletionAnnotation: "true"}
+			_, err = validator.ValidateUpdate(ctx, oldSchema, newSchema)
+			Expect(err).Should(Succeed())
+
+			By("And allow clearing it if namespace protects the resource by default")
+			oldSchema = aProtectedSchema("prod", ptr(true))
+			newSchema = oldSchema.DeepCopy()
+			newSchema.Spec.DeletionProtection = nil
+			_, err = validator.ValidateUpdate(ctx, oldSchema, newSchema)
+			Expect(err).Should(Succeed())
+		})
+		It("Should allow other updates of protected resource", func() {
+			oldSchema := aProtectedSchema("prod", nil)
+			newSchema := oldSchema.DeepCopy()
+			newSchema.Spec.Data.Schema = `"int"`
+
+			_, err := validator.ValidateUpdate(ctx, oldSchema, newSchema)
+			Expect(err).Should(Succeed())
+		})
+	})
+})
+
+func aProtectedSchema(namespace string, deletionProtection *bool) *KafkaSchema {
+	return &KafkaSchema{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: namespace},
+		Spec: KafkaSchemaSpec{
+			SubjectName:        "test",
+			CleanupPolicy:      SOFT,
+			DeletionProtection: deletionProtection,
+			Data:               KafkaSchemaData{Schema: `"string"`, Format: AVRO},
+		},
+	}
+}
+
+func ptr(b bool) *bool {
+	return &b
+}
+
+// namespaceReader serves namespaces (by name) with given labels
+type namespaceReader map[string]map[string]string
+
+func (r namespaceReader) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	namespace := obj.(*corev1.Namespace)
+	namespace.Name = key.Name
+	namespace.Labels = r[key.Name]
+	return nil
+}
+
+func (r namespaceReader) List(_ context.Context, _ client.ObjectList, _ ...client.ListOption) error {
+	return nil
+}
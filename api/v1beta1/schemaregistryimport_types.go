@@ -0,0 +1,106 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=ReadOnly;Managed
+type ImportMode string
+
+const (
+	ImportReadOnly ImportMode = "ReadOnly"
+	ImportManaged  ImportMode = "Managed"
+)
+
+// SchemaRegistryImportSpec defines the desired state of SchemaRegistryImport
+type SchemaRegistryImportSpec struct {
+	/*
+		SchemaRegistry optionally overrides controller default reference to schema registry subjects are imported from
+	*/
+	SchemaRegistry SchemaRegistry `json:"schemaRegistry,omitempty"`
+	/*
+		SubjectPattern is a regular expression subjects have to match to be imported.
+		If not provided, all subjects are imported
+	*/
+	SubjectPattern string `json:"subjectPattern,omitempty"`
+	/*
+		TargetNamespace is the namespace KafkaSchema resources are created in.
+		If not provided, resources are created in the namespace of SchemaRegistryImport
+	*/
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+	/*
+		NameTemplate is a Go template of KafkaSchema resource name, with {{ .Subject }} referring to subject name.
+		Result is converted to a valid resource name (lowercase, with unsupported characters replaced by "-").
+		If not provided, subject name is used
+	*/
+	NameTemplate string `json:"nameTemplate,omitempty"`
+	/*
+		Mode defines how imported KafkaSchema resources interact with schema registry:
+		ReadOnly: resources only adopt matching subjects, report drift (without correcting it) and never delete subjects
+		Managed: resources are fully managed by the operator, using its default policies
+
+		If not provided, ReadOnly is used
+	*/
+	Mode ImportMode `json:"mode,omitempty"`
+}
+
+type ImportedSubject struct {
+	// Subject is the name of imported schema registry subject
+	Subject string `json:"subject"`
+	// Resource is the name of KafkaSchema resource representing the subject
+	Resource string `json:"resource"`
+}
+
+// SchemaRegistryImportStatus defines the observed state of SchemaRegistryImport
+type SchemaRegistryImportStatus struct {
+	// Represents observations of the current state of SchemaRegistryImport.
+	// Operator uses single condition with type="Ready"
+	//
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// SchemaRegistryUrl is an effective URL of the schema registry subjects are imported from
+	SchemaRegistryUrl string `json:"schemaRegistryUrl,omitempty"`
+	// ImportedSubjects lists subjects matching SubjectPattern and their KafkaSchema resources
+	ImportedSubjects []ImportedSubject `json:"importedSubjects,omitempty"`
+	// LastImportTsEpoch timestamp of last import attempt, in epoch millis
+	LastImportTsEpoch int64 `json:"lastImportTsEpoch,omitempty"`
+}
+
+var (
+	ImportSubjects = ReadyReason{"ImportSubjects", metav1.ConditionFalse}
+	CreateResource = ReadyReason{"CreateResource", metav1.ConditionFalse}
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// SchemaRegistryImport is the Schema for the schemaregistryimports API.
+// It imports schema registry subjects as KafkaSchema resources
+type SchemaRegistryImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SchemaRegistryImportSpec   `json:"spec,omitempty"`
+	Status SchemaRegistryImportStatus `json:"status,omitempty"`
+}
+
+func (in *SchemaRegistryImport) SetReadyReason(reason ReadyReason, msg string) bool {
+	return setCondition(&in.Status.Conditions, in.Generation, "Ready", reason, msg)
+}
+
+//+kubebuilder:object:root=true
+
+// SchemaRegistryImportList contains a list of SchemaRegistryImport
+type SchemaRegistryImportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SchemaRegistryImport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SchemaRegistryImport{}, &SchemaRegistryImportList{})
+}
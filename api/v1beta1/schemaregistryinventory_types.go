@@ -0,0 +1,67 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type InventorySubject struct {
+	// Subject is the name of schema registry subject
+	Subject string `json:"subject"`
+	// Owner is the KafkaSchema resource (in "namespace/name" format) representing the subject
+	Owner string `json:"owner"`
+	// Created tells if the subject was created by the operator (on behalf of Owner)
+	Created bool `json:"created,omitempty"`
+	// OrphanedSince is the time when Owner was first reported missing (orphaned subjects only)
+	OrphanedSince *metav1.Time `json:"orphanedSince,omitempty"`
+	// Collectable tells if orphaned subject is eligible for garbage collection
+	// (it was created by the operator and its grace period has passed)
+	Collectable bool `json:"collectable,omitempty"`
+	/*
+		Released tells if finalizer of Owner released the subject (managed subjects only): the subject is left
+		in schema registry on purpose (e.g. DISABLED or VERSIONS cleanup policy, skip-cleanup annotation or subject
+		referenced by other subjects), so it's reported as unmanaged rather than orphaned once Owner is gone
+	*/
+	Released bool `json:"released,omitempty"`
+}
+
+// SchemaRegistryInventoryStatus defines the observed state of SchemaRegistryInventory
+type SchemaRegistryInventoryStatus struct {
+	// SchemaRegistryUrl is the URL of schema registry the inventory is reported for
+	SchemaRegistryUrl string `json:"schemaRegistryUrl,omitempty"`
+	// ManagedSubjects are subjects represented by existing KafkaSchema resources
+	ManagedSubjects []InventorySubject `json:"managedSubjects,omitempty"`
+	// UnmanagedSubjects are subjects that were never represented by KafkaSchema resources
+	UnmanagedSubjects []string `json:"unmanagedSubjects,omitempty"`
+	// SoftDeletedSubjects are subjects that were soft-deleted
+	SoftDeletedSubjects []string `json:"softDeletedSubjects,omitempty"`
+	// OrphanedSubjects are subjects whose KafkaSchema resources (Owner) no longer exist
+	OrphanedSubjects []InventorySubject `json:"orphanedSubjects,omitempty"`
+	// LastUpdateTsEpoch timestamp of last inventory update, in epoch millis
+	LastUpdateTsEpoch int64 `json:"lastUpdateTsEpoch,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+
+// SchemaRegistryInventory is the Schema for the schemaregistryinventories API.
+// It's maintained by the operator and reports subjects of single schema registry
+type SchemaRegistryInventory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status SchemaRegistryInventoryStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SchemaRegistryInventoryList contains a list of SchemaRegistryInventory
+type SchemaRegistryInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SchemaRegistryInventory `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SchemaRegistryInventory{}, &SchemaRegistryInventoryList{})
+}
@@ -5,16 +5,68 @@
 package v1beta1
 
 import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Adoption) DeepCopyInto(out *Adoption) {
+	*out = *in
+	in.AdoptedAt.DeepCopyInto(&out.AdoptedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Adoption.
+func (in *Adoption) DeepCopy() *Adoption {
+	if in == nil {
+		return nil
+	}
+	out := new(Adoption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportedSubject) DeepCopyInto(out *ImportedSubject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportedSubject.
+func (in *ImportedSubject) DeepCopy() *ImportedSubject {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportedSubject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventorySubject) DeepCopyInto(out *InventorySubject) {
+	*out = *in
+	if in.OrphanedSince != nil {
+		in, out := &in.OrphanedSince, &out.OrphanedSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventorySubject.
+func (in *InventorySubject) DeepCopy() *InventorySubject {
+	if in == nil {
+		return nil
+	}
+	out := new(InventorySubject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaSchema) DeepCopyInto(out *KafkaSchema) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -39,6 +91,41 @@ func (in *KafkaSchema) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaSchemaData) DeepCopyInto(out *KafkaSchemaData) {
 	*out = *in
+	if in.SchemaObject != nil {
+		in, out := &in.SchemaObject, &out.SchemaObject
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchemaFrom != nil {
+		in, out := &in.SchemaFrom, &out.SchemaFrom
+		*out = new(SchemaSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImportsFrom != nil {
+		in, out := &in.ImportsFrom, &out.ImportsFrom
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(Metadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetadataFromResource != nil {
+		in, out := &in.MetadataFromResource, &out.MetadataFromResource
+		*out = new(MetadataFromResource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleSet != nil {
+		in, out := &in.RuleSet, &out.RuleSet
+		*out = new(RuleSet)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.References != nil {
+		in, out := &in.References, &out.References
+		*out = make([]SchemaReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSchemaData.
@@ -86,8 +173,35 @@ func (in *KafkaSchemaList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaSchemaSpec) DeepCopyInto(out *KafkaSchemaSpec) {
 	*out = *in
+	if in.DeletionProtection != nil {
+		in, out := &in.DeletionProtection, &out.DeletionProtection
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SubjectConfig != nil {
+		in, out := &in.SubjectConfig, &out.SubjectConfig
+		*out = new(SubjectConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CleanupDelay != nil {
+		in, out := &in.CleanupDelay, &out.CleanupDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Ttl != nil {
+		in, out := &in.Ttl, &out.Ttl
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]SchemaVersion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.SchemaRegistry = in.SchemaRegistry
-	out.Data = in.Data
+	in.Data.DeepCopyInto(&out.Data)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSchemaSpec.
@@ -105,11 +219,25 @@ func (in *KafkaSchemaStatus) DeepCopyInto(out *KafkaSchemaStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RegisteredVersions != nil {
+		in, out := &in.RegisteredVersions, &out.RegisteredVersions
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Adoption != nil {
+		in, out := &in.Adoption, &out.Adoption
+		*out = new(Adoption)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScheduledCleanupTime != nil {
+		in, out := &in.ScheduledCleanupTime, &out.ScheduledCleanupTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSchemaStatus.
@@ -122,6 +250,96 @@ func (in *KafkaSchemaStatus) DeepCopy() *KafkaSchemaStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Metadata) DeepCopyInto(out *Metadata) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Sensitive != nil {
+		in, out := &in.Sensitive, &out.Sensitive
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metadata.
+func (in *Metadata) DeepCopy() *Metadata {
+	if in == nil {
+		return nil
+	}
+	out := new(Metadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataFromResource) DeepCopyInto(out *MetadataFromResource) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataFromResource.
+func (in *MetadataFromResource) DeepCopy() *MetadataFromResource {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataFromResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtobufFiles) DeepCopyInto(out *ProtobufFiles) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtobufFiles.
+func (in *ProtobufFiles) DeepCopy() *ProtobufFiles {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtobufFiles)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReadyReason) DeepCopyInto(out *ReadyReason) {
 	*out = *in
@@ -137,6 +355,77 @@ func (in *ReadyReason) DeepCopy() *ReadyReason {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rule) DeepCopyInto(out *Rule) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rule.
+func (in *Rule) DeepCopy() *Rule {
+	if in == nil {
+		return nil
+	}
+	out := new(Rule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleSet) DeepCopyInto(out *RuleSet) {
+	*out = *in
+	if in.MigrationRules != nil {
+		in, out := &in.MigrationRules, &out.MigrationRules
+		*out = make([]Rule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DomainRules != nil {
+		in, out := &in.DomainRules, &out.DomainRules
+		*out = make([]Rule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleSet.
+func (in *RuleSet) DeepCopy() *RuleSet {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaReference) DeepCopyInto(out *SchemaReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaReference.
+func (in *SchemaReference) DeepCopy() *SchemaReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchemaRegistry) DeepCopyInto(out *SchemaRegistry) {
 	*out = *in
@@ -151,3 +440,292 @@ func (in *SchemaRegistry) DeepCopy() *SchemaRegistry {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaRegistryImport) DeepCopyInto(out *SchemaRegistryImport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaRegistryImport.
+func (in *SchemaRegistryImport) DeepCopy() *SchemaRegistryImport {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaRegistryImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchemaRegistryImport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaRegistryImportList) DeepCopyInto(out *SchemaRegistryImportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SchemaRegistryImport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaRegistryImportList.
+func (in *SchemaRegistryImportList) DeepCopy() *SchemaRegistryImportList {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaRegistryImportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchemaRegistryImportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaRegistryImportSpec) DeepCopyInto(out *SchemaRegistryImportSpec) {
+	*out = *in
+	out.SchemaRegistry = in.SchemaRegistry
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaRegistryImportSpec.
+func (in *SchemaRegistryImportSpec) DeepCopy() *SchemaRegistryImportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaRegistryImportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaRegistryImportStatus) DeepCopyInto(out *SchemaRegistryImportStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImportedSubjects != nil {
+		in, out := &in.ImportedSubjects, &out.ImportedSubjects
+		*out = make([]ImportedSubject, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaRegistryImportStatus.
+func (in *SchemaRegistryImportStatus) DeepCopy() *SchemaRegistryImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaRegistryImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaRegistryInventory) DeepCopyInto(out *SchemaRegistryInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaRegistryInventory.
+func (in *SchemaRegistryInventory) DeepCopy() *SchemaRegistryInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaRegistryInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchemaRegistryInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaRegistryInventoryList) DeepCopyInto(out *SchemaRegistryInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SchemaRegistryInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaRegistryInventoryList.
+func (in *SchemaRegistryInventoryList) DeepCopy() *SchemaRegistryInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaRegistryInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchemaRegistryInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaRegistryInventoryStatus) DeepCopyInto(out *SchemaRegistryInventoryStatus) {
+	*out = *in
+	if in.ManagedSubjects != nil {
+		in, out := &in.ManagedSubjects, &out.ManagedSubjects
+		*out = make([]InventorySubject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UnmanagedSubjects != nil {
+		in, out := &in.UnmanagedSubjects, &out.UnmanagedSubjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SoftDeletedSubjects != nil {
+		in, out := &in.SoftDeletedSubjects, &out.SoftDeletedSubjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OrphanedSubjects != nil {
+		in, out := &in.OrphanedSubjects, &out.OrphanedSubjects
+		*out = make([]InventorySubject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaRegistryInventoryStatus.
+func (in *SchemaRegistryInventoryStatus) DeepCopy() *SchemaRegistryInventoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaRegistryInventoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaSource) DeepCopyInto(out *SchemaSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProtobufFiles != nil {
+		in, out := &in.ProtobufFiles, &out.ProtobufFiles
+		*out = new(ProtobufFiles)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaSource.
+func (in *SchemaSource) DeepCopy() *SchemaSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaVersion) DeepCopyInto(out *SchemaVersion) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaVersion.
+func (in *SchemaVersion) DeepCopy() *SchemaVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubjectConfig) DeepCopyInto(out *SubjectConfig) {
+	*out = *in
+	if in.Normalize != nil {
+		in, out := &in.Normalize, &out.Normalize
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultMetadata != nil {
+		in, out := &in.DefaultMetadata, &out.DefaultMetadata
+		*out = new(Metadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OverrideMetadata != nil {
+		in, out := &in.OverrideMetadata, &out.OverrideMetadata
+		*out = new(Metadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultRuleSet != nil {
+		in, out := &in.DefaultRuleSet, &out.DefaultRuleSet
+		*out = new(RuleSet)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OverrideRuleSet != nil {
+		in, out := &in.OverrideRuleSet, &out.OverrideRuleSet
+		*out = new(RuleSet)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubjectConfig.
+func (in *SubjectConfig) DeepCopy() *SubjectConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SubjectConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
 	"time"
 
@@ -15,10 +16,13 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -89,6 +93,13 @@ func main() {
 			SecureServing: secureMetrics,
 			TLSOpts:       tlsOpts,
 		},
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				// Secrets referenced by KafkaSchemas are read directly (only their metadata is watched),
+				// so that content of every Secret in the cluster isn't cached
+				DisableFor: []client.Object{&corev1.Secret{}},
+			},
+		},
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -110,14 +121,50 @@ func main() {
 		os.Exit(1)
 	}
 
+	// deletion protection is enforced by the webhook only
+	var deletionProtection *v1beta1.KafkaSchemaValidator
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		deletionProtection = &v1beta1.KafkaSchemaValidator{
+			Reader:              mgr.GetClient(),
+			ProtectedNamespaces: deletionProtectionSelector(),
+		}
+	}
+
 	if err = (&controller.KafkaSchemaReconciler{
+		RequeueDelay:       requeueDelay(),
+		DeletionProtection: deletionProtection,
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		Recorder:           mgr.GetEventRecorderFor("kafkaschema-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KafkaSchema")
+		os.Exit(1)
+	}
+	if err = (&controller.SchemaRegistryImportReconciler{
 		RequeueDelay: requeueDelay(),
 		Client:       mgr.GetClient(),
 		Scheme:       mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "KafkaSchema")
+		setupLog.Error(err, "unable to create controller", "controller", "SchemaRegistryImport")
 		os.Exit(1)
 	}
+	if deletionProtection != nil {
+		if err = (&v1beta1.KafkaSchema{}).SetupWebhookWithManager(mgr, deletionProtection); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "KafkaSchema")
+			os.Exit(1)
+		}
+	}
+	if interval := inventoryInterval(); interval > 0 {
+		if err = mgr.Add(&controller.SchemaRegistryInventoryReporter{
+			Interval:      interval,
+			GcPolicy:      orphanGcPolicy(),
+			GcGracePeriod: durationFromEnv("ORPHAN_GC_GRACE_PERIOD", "orphan GC grace period", 24*time.Hour),
+			Client:        mgr.GetClient(),
+		}); err != nil {
+			setupLog.Error(err, "unable to create inventory reporter")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -137,14 +184,50 @@ func main() {
 }
 
 func requeueDelay() time.Duration {
-	delayString := os.Getenv("REQUEUE_DELAY")
-	if len(delayString) == 0 {
-		setupLog.Info("Using default requeue delay=1m (60 seconds)")
-		return 1 * time.Minute
+	return durationFromEnv("REQUEUE_DELAY", "requeue delay", 1*time.Minute)
+}
+
+func inventoryInterval() time.Duration {
+	return durationFromEnv("INVENTORY_INTERVAL", "inventory interval", 5*time.Minute)
+}
+
+func orphanGcPolicy() controller.OrphanGcPolicy {
+	policy := controller.OrphanGcPolicy(os.Getenv("ORPHAN_GC_POLICY"))
+	switch policy {
+	case "":
+		return controller.GcDisabled
+	case controller.GcDisabled, controller.GcReport, controller.GcSoft, controller.GcHard:
+		return policy
+	default:
+		setupLog.Error(fmt.Errorf("unsupported policy %q", policy), "unable to parse ORPHAN_GC_POLICY")
+		os.Exit(1)
+		return policy
+	}
+}
+
+// deletionProtectionSelector selects namespaces whose KafkaSchemas are protected from deletion by default
+func deletionProtectionSelector() labels.Selector {
+	selectorString := os.Getenv("DELETION_PROTECTION_NAMESPACE_SELECTOR")
+	if len(selectorString) == 0 {
+		return nil
+	}
+	selector, err := labels.Parse(selectorString)
+	if err != nil {
+		setupLog.Error(err, "unable to parse DELETION_PROTECTION_NAMESPACE_SELECTOR as label selector "+selectorString)
+		os.Exit(1)
+	}
+	return selector
+}
+
+func durationFromEnv(key string, name string, defaultDuration time.Duration) time.Duration {
+	durationString := os.Getenv(key)
+	if len(durationString) == 0 {
+		setupLog.Info(fmt.Sprintf("Using default %s=%s", name, defaultDuration))
+		return defaultDuration
 	} else {
-		duration, err := time.ParseDuration(delayString)
+		duration, err := time.ParseDuration(durationString)
 		if err != nil {
-			setupLog.Error(err, "unable to parse REQUEUE_DELAY as time.Duration "+delayString)
+			setupLog.Error(err, "unable to parse "+key+" as time.Duration "+durationString)
 			os.Exit(1)
 		}
 		return duration
@@ -0,0 +1,553 @@
+/*
+Package avroidl compiles Avro IDL (https://avro.apache.org/docs/1.12.0/idl-language/)
+to Avro JSON schema, so that schemas authored in IDL can be registered in schema registry
+*/
+package avroidl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Error is compile error of Avro IDL pointing to the line (and column) of file it was found at
+type Error struct {
+	// File is the path of imported file, empty for the compiled source itself
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	location := fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+	if len(e.File) > 0 {
+		location = e.File + " " + location
+	}
+	return location + ": " + e.Msg
+}
+
+// ReadFile returns content of file imported by IDL (path is relative to the compiled source)
+type ReadFile func(path string) (string, error)
+
+/*
+Compile compiles Avro IDL to JSON schema of its main type: the one declared with "schema <type>;"
+or the last named type (other than error) declared in the source otherwise (e.g. within protocol).
+Named types are defined where they are referenced first, types not referenced by the main one are left out.
+Default values of fields are validated against their types
+*/
+func Compile(src string, readFile ReadFile) (string, error) {
+	c := &compiler{readFile: readFile, types: map[string]*namedSchema{}, imported: map[string]bool{}}
+	file, err := c.load("", src)
+	if err != nil {
+		return "", err
+	}
+	main := file.main
+	if main == nil {
+		// errors are thrown by protocol messages, they aren't meant to be the schema
+		for _, named := range file.named {
+			if named.kind != "error" {
+				main = &schemaType{tok: named.tok, kind: "reference", name: named.fullName()}
+			}
+		}
+		if main == nil {
+			return "", &Error{Line: 1, Column: 1, Msg: "no named type (other than error) declared, nothing to compile"}
+		}
+	}
+	schema, err := c.emit(main, "", map[string]bool{})
+	if err != nil {
+		return "", err
+	}
+	serialized := &bytes.Buffer{}
+	encoder := json.NewEncoder(serialized)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(schema); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(serialized.String(), "\n"), nil
+}
+
+type compiler struct {
+	readFile ReadFile
+	// types are named types by their full names
+	types    map[string]*namedSchema
+	imported map[string]bool
+}
+
+// load parses IDL file and registers types it declares and imports
+func (c *compiler) load(filePath string, src string) (*parsedFile, error) {
+	file, err := parse(filePath, src)
+	if err != nil {
+		return nil, err
+	}
+	for _, decl := range file.imports {
+		if err := c.importFile(filePath, decl); err != nil {
+			return nil, err
+		}
+	}
+	for _, named := range file.named {
+		if err := c.define(named); err != nil {
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+func (c *compiler) importFile(filePath string, decl importDecl) error {
+	importPath := path.Join(path.Dir(filePath), decl.path)
+	if c.imported[importPath] {
+		return nil
+	}
+	c.imported[importPath] = true
+	fail := func(format string, args ...interface{}) error {
+		return &Error{File: filePath, Line: decl.tok.line, Column: decl.tok.column, Msg: fmt.Sprintf(format, args...)}
+	}
+	if c.readFile == nil {
+		return fail("can't import %s, imports aren't available", decl.path)
+	}
+	content, err := c.readFile(importPath)
+	if err != nil {
+		return fail("failed to import %s: %s", decl.path, err)
+	}
+	if decl.kind == "idl" {
+		_, err = c.load(importPath, content)
+		return err
+	}
+	value, err := decodeJSON(content)
+	if err != nil {
+		return fail("failed to import %s: invalid JSON: %s", decl.path, err)
+	}
+	if decl.kind == "protocol" {
+		protocol, ok := value.(object)
+		if !ok {
+			return fail("failed to import %s: protocol has to be JSON object", decl.path)
+		}
+		namespace, _ := protocol.get("namespace")
+		namespaceString, _ := namespace.(string)
+		types, _ := protocol.get("types")
+		return c.defineJSON(decl, types, namespaceString, nil)
+	}
+	return c.defineJSON(decl, value, "", nil)
+}
+
+func (c *compiler) define(named *namedSchema) error {
+	if _, ok := c.types[named.fullName()]; ok {
+		return &Error{File: named.tok.file, Line: named.tok.line, Column: named.tok.column,
+			Msg: fmt.Sprintf("type %s is already defined", named.fullName())}
+	}
+	c.types[named.fullName()] = named
+	return nil
+}
+
+/*
+defineJSON registers named types defined by imported JSON schema (or protocol types).
+Names are qualified in place, so the definitions stay valid wherever they are emitted
+*/
+func (c *compiler) defineJSON(decl importDecl, value interface{}, namespace string, outer *namedSchema) error {
+	switch value := value.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if err := c.defineJSON(decl, item, namespace, outer); err != nil {
+				return err
+			}
+		}
+	case object:
+		typ, _ := value.get("type")
+		kind, _ := typ.(string)
+		if kind != "record" && kind != "error" && kind != "enum" && kind != "fixed" {
+			if typ != nil {
+				if err := c.defineJSON(decl, typ, namespace, outer); err != nil {
+					return err
+				}
+			}
+			for _, key := range []string{"items", "values"} {
+				if nested, ok := value.get(key); ok {
+					if err := c.defineJSON(decl, nested, namespace, outer); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		name, _ := value.get("name")
+		nameString, _ := name.(string)
+		if ns, ok := value.get("namespace"); ok && !strings.Contains(nameString, ".") {
+			namespace, _ = ns.(string)
+		}
+		named := &namedSchema{tok: decl.tok, kind: kind, raw: value}
+		named.namespace, named.name = splitName(nameString, namespace)
+		value.set("name", named.fullName())
+		if err := c.define(named); err != nil {
+			return err
+		}
+		if outer != nil {
+			outer.nested = append(outer.nested, named.fullName())
+		} else {
+			outer = named
+		}
+		fields, _ := value.get("fields")
+		fieldList, _ := fields.([]interface{})
+		for _, f := range fieldList {
+			if fieldObject, ok := f.(object); ok {
+				fieldType, _ := fieldObject.get("type")
+				if err := c.defineJSON(decl, fieldType, named.namespace, outer); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns named type referenced by (possibly unqualified) name
+func (c *compiler) resolve(typ *schemaType) (*namedSchema, error) {
+	if named, ok := c.types[qualify(typ.namespace, typ.name)]; ok && !strings.Contains(typ.name, ".") {
+		return named, nil
+	}
+	if named, ok := c.types[typ.name]; ok {
+		return named, nil
+	}
+	return nil, &Error{File: typ.tok.file, Line: typ.tok.line, Column: typ.tok.column,
+		Msg: fmt.Sprintf("undefined type %s", typ.name)}
+}
+
+/*
+emit returns JSON schema of the type within enclosing namespace.
+Named types are defined on first occurrence and referenced by name afterwards
+*/
+func (c *compiler) emit(typ *schemaType, enclosing string, emitted map[string]bool) (interface{}, error) {
+	var schema interface{}
+	switch typ.kind {
+	case "primitive":
+		schema = typ.name
+	case "reference":
+		named, err := c.resolve(typ)
+		if err != nil {
+			return nil, err
+		}
+		return c.emitNamed(named, enclosing, emitted)
+	case "array", "map":
+		items, err := c.emit(typ.items, enclosing, emitted)
+		if err != nil {
+			return nil, err
+		}
+		key := "items"
+		if typ.kind == "map" {
+			key = "values"
+		}
+		schema = object{{"type", typ.kind}, {key, items}}
+	case "union", "nullable":
+		var branches []interface{}
+		for _, branch := range typ.branches {
+			emittedBranch, err := c.emit(branch, enclosing, emitted)
+			if err != nil {
+				return nil, err
+			}
+			branches = append(branches, emittedBranch)
+		}
+		return branches, nil
+	}
+	if len(typ.props) == 0 {
+		return schema, nil
+	}
+	if complexSchema, ok := schema.(object); ok {
+		return append(complexSchema, typ.props...), nil
+	}
+	return append(object{{"type", schema}}, typ.props...), nil
+}
+
+func (c *compiler) emitNamed(named *namedSchema, enclosing string, emitted map[string]bool) (interface{}, error) {
+	if emitted[named.fullName()] {
+		if named.namespace == enclosing {
+			return named.name, nil
+		}
+		return named.fullName(), nil
+	}
+	emitted[named.fullName()] = true
+	if named.raw != nil {
+		for _, nested := range named.nested {
+			emitted[nested] = true
+		}
+		return named.raw, nil
+	}
+
+	schema := object{{"type", named.kind}, {"name", named.name}}
+	if named.namespace != enclosing {
+		schema = append(schema, member{"namespace", named.namespace})
+	}
+	if len(named.doc) > 0 {
+		schema = append(schema, member{"doc", named.doc})
+	}
+	switch named.kind {
+	case "record", "error":
+		fields := []interface{}{}
+		for _, f := range named.fields {
+			emittedField, err := c.emitField(f, named.namespace, emitted)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, emittedField)
+		}
+		schema = append(schema, member{"fields", fields})
+	case "enum":
+		symbols := append([]string{}, named.symbols...)
+		schema = append(schema, member{"symbols", symbols})
+		if len(named.enumDefault) > 0 {
+			schema = append(schema, member{"default", named.enumDefault})
+		}
+	case "fixed":
+		schema = append(schema, member{"size", named.size})
+	}
+	return append(schema, named.props...), nil
+}
+
+func (c *compiler) emitField(f *field, enclosing string, emitted map[string]bool) (object, error) {
+	typ := f.typ
+	if f.hasDefault {
+		// union has to start with type of the default value
+		typ = nullableDefaultType(typ, f.defaultValue)
+		if expected, err := c.checkDefault(typ, f.defaultValue); err != nil {
+			return nil, err
+		} else if len(expected) > 0 {
+			return nil, &Error{File: f.defaultTok.file, Line: f.defaultTok.line, Column: f.defaultTok.column,
+				Msg: fmt.Sprintf("default value of field %s doesn't match %s", f.name, expected)}
+		}
+	}
+	emittedType, err := c.emit(typ, enclosing, emitted)
+	if err != nil {
+		return nil, err
+	}
+	schema := object{{"name", f.name}, {"type", emittedType}}
+	if len(f.doc) > 0 {
+		schema = append(schema, member{"doc", f.doc})
+	}
+	if f.hasDefault {
+		schema = append(schema, member{"default", f.defaultValue})
+	}
+	return append(schema, f.props...), nil
+}
+
+// nullableDefaultType starts nullable type with the non-null type if it's the type of the (non-null) default value
+func nullableDefaultType(typ *schemaType, value interface{}) *schemaType {
+	if typ.kind != "nullable" || value == nil {
+		return typ
+	}
+	return &schemaType{tok: typ.tok, kind: "nullable", branches: []*schemaType{typ.branches[1], typ.branches[0]}}
+}
+
+/*
+checkDefault checks (JSON) default value against the type, union default has to match its first type.
+Returns description of the expected type if the value doesn't match, empty string otherwise.
+Types imported from JSON schemas are left to schema registry
+*/
+func (c *compiler) checkDefault(typ *schemaType, value interface{}) (string, error) {
+	switch typ.kind {
+	case "primitive":
+		if !matchesPrimitive(typ.name, value) {
+			return typ.name, nil
+		}
+	case "reference":
+		named, err := c.resolve(typ)
+		if err != nil {
+			return "", err
+		}
+		return c.checkNamedDefault(named, value)
+	case "array":
+		values, ok := value.([]interface{})
+		if !ok {
+			return "array", nil
+		}
+		for _, item := range values {
+			if expected, err := c.checkDefault(typ.items, item); err != nil || len(expected) > 0 {
+				return "array of " + expected, err
+			}
+		}
+	case "map":
+		values, ok := value.(object)
+		if !ok {
+			return "map", nil
+		}
+		for _, m := range values {
+			if expected, err := c.checkDefault(typ.items, m.Value); err != nil || len(expected) > 0 {
+				return "map of " + expected, err
+			}
+		}
+	case "union", "nullable":
+		if expected, err := c.checkDefault(typ.branches[0], value); err != nil || len(expected) > 0 {
+			return "first type of union " + expected, err
+		}
+	}
+	return "", nil
+}
+
+func (c *compiler) checkNamedDefault(named *namedSchema, value interface{}) (string, error) {
+	if named.raw != nil {
+		return "", nil
+	}
+	switch named.kind {
+	case "record", "error":
+		values, ok := value.(object)
+		if !ok {
+			return named.kind + " " + named.name, nil
+		}
+		for _, f := range named.fields {
+			fieldValue, ok := values.get(f.name)
+			if !ok {
+				if f.hasDefault {
+					continue
+				}
+				return fmt.Sprintf("%s %s (missing field %s)", named.kind, named.name, f.name), nil
+			}
+			if expected, err := c.checkDefault(nullableDefaultType(f.typ, fieldValue), fieldValue); err != nil ||
+				len(expected) > 0 {
+				return fmt.Sprintf("%s %s (field %s: %s)", named.kind, named.name, f.name, expected), err
+			}
+		}
+	case "enum":
+		symbol, _ := value.(string)
+		if !slices.Contains(named.symbols, symbol) {
+			return "enum " + named.name, nil
+		}
+	case "fixed":
+		if _, ok := value.(string); !ok {
+			return "fixed " + named.name, nil
+		}
+	}
+	return "", nil
+}
+
+// matchesPrimitive tells if JSON value is valid default of primitive type
+func matchesPrimitive(name string, value interface{}) bool {
+	switch name {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "int", "long":
+		number, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		bitSize := 64
+		if name == "int" {
+			bitSize = 32
+		}
+		_, err := strconv.ParseInt(number.String(), 10, bitSize)
+		return err == nil
+	case "float", "double":
+		number, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := number.Float64()
+		return err == nil
+	case "bytes", "string":
+		_, ok := value.(string)
+		return ok
+	}
+	return true
+}
+
+// object is JSON object preserving order of its members
+type object []member
+
+type member struct {
+	Key   string
+	Value interface{}
+}
+
+func (o object) get(key string) (interface{}, bool) {
+	for _, m := range o {
+		if m.Key == key {
+			return m.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (o object) set(key string, value interface{}) {
+	for i := range o {
+		if o[i].Key == key {
+			o[i].Value = value
+		}
+	}
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		for j, value := range []interface{}{m.Key, m.Value} {
+			if j > 0 {
+				buf.WriteByte(':')
+			}
+			encoder := json.NewEncoder(buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(value); err != nil {
+				return nil, err
+			}
+			buf.Truncate(buf.Len() - 1)
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeJSON decodes JSON preserving order of object members and numbers as written
+func decodeJSON(content string) (interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	value, err := decodeJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected content after JSON value")
+	}
+	return value, nil
+}
+
+func decodeJSONValue(decoder *json.Decoder) (interface{}, error) {
+	tok, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('['):
+		values := []interface{}{}
+		for decoder.More() {
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		_, err := decoder.Token()
+		return values, err
+	case json.Delim('{'):
+		values := object{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, member{key.(string), value})
+		}
+		_, err := decoder.Token()
+		return values, err
+	}
+	return tok, nil
+}
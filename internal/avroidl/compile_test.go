@@ -0,0 +1,243 @@
+package avroidl
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	files := map[string]string{
+		"common/money.avdl": `
+namespace org.example.common;
+/** Amount in given currency */
+record Money {
+  decimal(9, 2) amount;
+  Currency currency = "EUR";
+}
+enum Currency { EUR, USD } = EUR;`,
+		"address.avsc": `{"type": "record", "name": "Address", "namespace": "org.example.geo",
+  "fields": [{"name": "street", "type": "string"}]}`,
+	}
+	readFile := func(path string) (string, error) {
+		content, ok := files[path]
+		if !ok {
+			return "", fmt.Errorf("file not found")
+		}
+		return content, nil
+	}
+
+	tests := []struct {
+		name     string
+		idl      string
+		expected string
+	}{
+		{
+			name: "protocol",
+			idl: `
+@namespace("org.example")
+protocol Orders {
+  fixed Hash(16);
+  enum Status { NEW, PAID }
+
+  /** Order placed by customer */
+  @aliases(["PurchaseOrder"])
+  record Order {
+    long id;
+    /** Customer's note */
+    string? note = null;
+    string? channel = "web";
+    union { null, int, string } ref;
+    array<Hash> hashes = [];
+    map<Status> statuses;
+    timestamp_ms created;
+    date @order("ignore") due;
+    @logicalType("timestamp-micros") long ` + "`error`" + `;
+    Status status = "NEW";
+  }
+
+  Order get(long id) throws Failure;
+  void cancel(long id) oneway;
+}`,
+			expected: `{"type":"record","name":"Order","namespace":"org.example","doc":"Order placed by customer","fields":[` +
+				`{"name":"id","type":"long"},` +
+				`{"name":"note","type":["null","string"],"doc":"Customer's note","default":null},` +
+				`{"name":"channel","type":["string","null"],"default":"web"},` +
+				`{"name":"ref","type":["null","int","string"]},` +
+				`{"name":"hashes","type":{"type":"array","items":{"type":"fixed","name":"Hash","size":16}},"default":[]},` +
+				`{"name":"statuses","type":{"type":"map","values":{"type":"enum","name":"Status","symbols":["NEW","PAID"]}}},` +
+				`{"name":"created","type":{"type":"long","logicalType":"timestamp-millis"}},` +
+				`{"name":"due","type":{"type":"int","logicalType":"date"},"order":"ignore"},` +
+				`{"name":"error","type":{"type":"long","logicalType":"timestamp-micros"}},` +
+				`{"name":"status","type":"Status","default":"NEW"}],"aliases":["PurchaseOrder"]}`,
+		},
+		{
+			name: "schema syntax with imports",
+			idl: `
+namespace org.example;
+schema Invoice;
+
+import idl "common/money.avdl";
+import schema "address.avsc";
+
+record Invoice {
+  org.example.common.Money total;
+  org.example.common.Money? discount;
+  org.example.geo.Address billing;
+  org.example.geo.Address shipping;
+  uuid id;
+}
+
+record Unused {}`,
+			expected: `{"type":"record","name":"Invoice","namespace":"org.example","fields":[` +
+				`{"name":"total","type":{"type":"record","name":"Money","namespace":"org.example.common","doc":"Amount in given currency","fields":[` +
+				`{"name":"amount","type":{"type":"bytes","logicalType":"decimal","precision":9,"scale":2}},` +
+				`{"name":"currency","type":{"type":"enum","name":"Currency","symbols":["EUR","USD"],"default":"EUR"},"default":"EUR"}]}},` +
+				`{"name":"discount","type":["null","org.example.common.Money"]},` +
+				`{"name":"billing","type":{"type":"record","name":"org.example.geo.Address","namespace":"org.example.geo",` +
+				`"fields":[{"name":"street","type":"string"}]}},` +
+				`{"name":"shipping","type":"org.example.geo.Address"},` +
+				`{"name":"id","type":{"type":"string","logicalType":"uuid"}}]}`,
+		},
+		{
+			name:     "schema of unnamed type",
+			idl:      `schema array<string>;`,
+			expected: `{"type":"array","items":"string"}`,
+		},
+		{
+			name: "protocol with errors",
+			idl: `
+protocol Orders {
+  record Order { long id; }
+  error Failure { string reason; }
+}`,
+			expected: `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"}]}`,
+		},
+		{
+			name: "defaults of complex types",
+			idl: `
+schema Order;
+record Order {
+  Line line = {"sku": "A-1", "quantity": 2};
+  map<long> totals = {"EUR": 100};
+  union { null, Line } previous = null;
+}
+enum Unit { PIECE, KG }
+record Line { string sku; int quantity; Unit unit = "PIECE"; }`,
+			expected: `{"type":"record","name":"Order","fields":[` +
+				`{"name":"line","type":{"type":"record","name":"Line","fields":[{"name":"sku","type":"string"},` +
+				`{"name":"quantity","type":"int"},` +
+				`{"name":"unit","type":{"type":"enum","name":"Unit","symbols":["PIECE","KG"]},"default":"PIECE"}]},` +
+				`"default":{"sku":"A-1","quantity":2}},` +
+				`{"name":"totals","type":{"type":"map","values":"long"},"default":{"EUR":100}},` +
+				`{"name":"previous","type":["null","Line"],"default":null}]}`,
+		},
+		{
+			name: "defaults of nested optional fields",
+			idl: `
+schema Order;
+record Order {
+  Note note = {"f": "x"};
+  Note empty = {"f": null};
+}
+record Note { string? f; }`,
+			expected: `{"type":"record","name":"Order","fields":[` +
+				`{"name":"note","type":{"type":"record","name":"Note","fields":[{"name":"f","type":["null","string"]}]},` +
+				`"default":{"f":"x"}},` +
+				`{"name":"empty","type":"Note","default":{"f":null}}]}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			compiled, err := Compile(test.idl, readFile)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if compiled != test.expected {
+				t.Errorf("unexpected schema\nexpected:\t%s\nactual:\t\t%s", test.expected, compiled)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	readFile := func(path string) (string, error) {
+		if path == "broken.avdl" {
+			return "record Broken {\n  string\n}", nil
+		}
+		return "", fmt.Errorf("file not found")
+	}
+	tests := []struct {
+		name     string
+		idl      string
+		expected string
+	}{
+		{
+			name:     "syntax error",
+			idl:      "record Order {\n  long id\n}",
+			expected: `line 3, column 1: expected ";", got "}"`,
+		},
+		{
+			name:     "undefined type",
+			idl:      "record Order {\n  long id;\n  Customer customer;\n}",
+			expected: `line 3, column 3: undefined type Customer`,
+		},
+		{
+			name:     "duplicate type",
+			idl:      "enum Status { NEW }\nrecord Status {}",
+			expected: `line 2, column 8: type Status is already defined`,
+		},
+		{
+			name:     "missing import",
+			idl:      "namespace org.example;\nimport idl \"missing.avdl\";",
+			expected: `line 2, column 12: failed to import missing.avdl: file not found`,
+		},
+		{
+			name:     "error in imported file",
+			idl:      "import idl \"broken.avdl\";\nrecord Order {}",
+			expected: `broken.avdl line 3, column 1: expected field name, got "}"`,
+		},
+		{
+			name:     "default not matching field type",
+			idl:      "record Order {\n  int x = \"bad\";\n}",
+			expected: `line 2, column 11: default value of field x doesn't match int`,
+		},
+		{
+			name:     "default not matching first type of union",
+			idl:      "record Order {\n  union { null, int } x = 5;\n}",
+			expected: `line 2, column 27: default value of field x doesn't match first type of union null`,
+		},
+		{
+			name:     "default out of int range",
+			idl:      "record Order {\n  int x = 2147483648;\n}",
+			expected: `line 2, column 11: default value of field x doesn't match int`,
+		},
+		{
+			name:     "default not matching enum symbols",
+			idl:      "enum Status { NEW }\nrecord Order {\n  Status status = \"OLD\";\n}",
+			expected: `line 3, column 19: default value of field status doesn't match enum Status`,
+		},
+		{
+			name:     "default of record missing field",
+			idl:      "record Line { string sku; }\nrecord Order {\n  Line line = {};\n}",
+			expected: `line 3, column 15: default value of field line doesn't match record Line (missing field sku)`,
+		},
+		{
+			name:     "protocol declaring errors only",
+			idl:      "protocol Orders {\n  error Failure {}\n}",
+			expected: `line 1, column 1: no named type (other than error) declared, nothing to compile`,
+		},
+		{
+			name:     "unterminated string",
+			idl:      "record Order {\n  string note = \"abc;\n}",
+			expected: `line 2, column 17: unterminated string`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Compile(test.idl, readFile)
+			if err == nil || err.Error() != test.expected {
+				t.Errorf("expected error %q, got %v", test.expected, err)
+			}
+		})
+	}
+}
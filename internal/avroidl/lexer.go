@@ -0,0 +1,245 @@
+package avroidl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenAnnotation
+	tokenPunct
+)
+
+// token is lexical token of Avro IDL with its position (1-based) and doc comment preceding it
+type token struct {
+	kind tokenKind
+	text string
+	// quoted is set for identifiers escaped with backticks, which are never keywords
+	quoted bool
+	doc    string
+	file   string
+	line   int
+	column int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of file"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	case tokenAnnotation:
+		return "@" + t.text
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// is tells if the token is given punctuation or (unquoted) keyword
+func (t token) is(text string) bool {
+	return (t.kind == tokenPunct || t.kind == tokenIdent && !t.quoted) && t.text == text
+}
+
+// lexer splits Avro IDL into tokens, skipping whitespace and comments (doc comments are attached to the next token)
+type lexer struct {
+	file   string
+	src    []rune
+	pos    int
+	line   int
+	column int
+	doc    string
+}
+
+func newLexer(file string, src string) *lexer {
+	return &lexer{file: file, src: []rune(src), line: 1, column: 1}
+}
+
+func (l *lexer) errorf(line int, column int, format string, args ...interface{}) error {
+	return &Error{File: l.file, Line: line, Column: column, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (l *lexer) peekRune(offset int) rune {
+	if l.pos+offset >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos+offset]
+}
+
+func (l *lexer) advance() rune {
+	r := l.src[l.pos]
+	l.pos++
+	if r == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	return r
+}
+
+// tokens returns all tokens of the source, terminated with tokenEOF
+func (l *lexer) tokens() ([]token, error) {
+	var tokens []token
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	if err := l.skipWhitespaceAndComments(); err != nil {
+		return token{}, err
+	}
+	tok := token{file: l.file, line: l.line, column: l.column, doc: l.doc}
+	l.doc = ""
+	if l.pos >= len(l.src) {
+		tok.kind = tokenEOF
+		return tok, nil
+	}
+	r := l.peekRune(0)
+	switch {
+	case isIdentStart(r):
+		tok.kind = tokenIdent
+		tok.text = l.readWhile(isIdentPart)
+	case r == '`':
+		l.advance()
+		tok.kind = tokenIdent
+		tok.quoted = true
+		tok.text = l.readWhile(isIdentPart)
+		if l.peekRune(0) != '`' || len(tok.text) == 0 {
+			return tok, l.errorf(tok.line, tok.column, "unterminated quoted identifier")
+		}
+		l.advance()
+	case r == '@':
+		l.advance()
+		tok.kind = tokenAnnotation
+		tok.text = l.readWhile(func(r rune) bool { return isIdentPart(r) || r == '-' })
+		if len(tok.text) == 0 {
+			return tok, l.errorf(tok.line, tok.column, "annotation name expected after @")
+		}
+	case r == '"':
+		tok.kind = tokenString
+		text, err := l.readString()
+		if err != nil {
+			return tok, l.errorf(tok.line, tok.column, "%s", err)
+		}
+		tok.text = text
+	case r == '-' || unicode.IsDigit(r):
+		tok.kind = tokenNumber
+		tok.text = l.readNumber()
+		if !json.Valid([]byte(tok.text)) {
+			return tok, l.errorf(tok.line, tok.column, "invalid number %s", tok.text)
+		}
+	case strings.ContainsRune("{}()[]<>,;:=?", r):
+		tok.kind = tokenPunct
+		tok.text = string(l.advance())
+	default:
+		return tok, l.errorf(tok.line, tok.column, "unexpected character %q", r)
+	}
+	return tok, nil
+}
+
+func (l *lexer) skipWhitespaceAndComments() error {
+	for l.pos < len(l.src) {
+		r := l.peekRune(0)
+		switch {
+		case unicode.IsSpace(r):
+			l.advance()
+		case r == '/' && l.peekRune(1) == '/':
+			l.readWhile(func(r rune) bool { return r != '\n' })
+		case r == '/' && l.peekRune(1) == '*':
+			line, column := l.line, l.column
+			isDoc := l.peekRune(2) == '*' && l.peekRune(3) != '/'
+			l.advance()
+			l.advance()
+			start := l.pos
+			for !(l.peekRune(0) == '*' && l.peekRune(1) == '/') {
+				if l.pos >= len(l.src) {
+					return l.errorf(line, column, "unterminated comment")
+				}
+				l.advance()
+			}
+			comment := string(l.src[start:l.pos])
+			l.advance()
+			l.advance()
+			if isDoc {
+				l.doc = docComment(comment)
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *lexer) readWhile(accept func(rune) bool) string {
+	start := l.pos
+	for l.pos < len(l.src) && accept(l.peekRune(0)) {
+		l.advance()
+	}
+	return string(l.src[start:l.pos])
+}
+
+func (l *lexer) readString() (string, error) {
+	start := l.pos
+	l.advance()
+	for {
+		if l.pos >= len(l.src) || l.peekRune(0) == '\n' {
+			return "", fmt.Errorf("unterminated string")
+		}
+		r := l.advance()
+		if r == '\\' && l.pos < len(l.src) {
+			l.advance()
+		} else if r == '"' {
+			break
+		}
+	}
+	var text string
+	if err := json.Unmarshal([]byte(string(l.src[start:l.pos])), &text); err != nil {
+		return "", fmt.Errorf("invalid string: %w", err)
+	}
+	return text, nil
+}
+
+func (l *lexer) readNumber() string {
+	start := l.pos
+	if l.peekRune(0) == '-' {
+		l.advance()
+	}
+	l.readWhile(func(r rune) bool {
+		return unicode.IsDigit(r) || r == '.' || r == 'e' || r == 'E' ||
+			(r == '+' || r == '-') && (l.src[l.pos-1] == 'e' || l.src[l.pos-1] == 'E')
+	})
+	return string(l.src[start:l.pos])
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// docComment strips comment markers and leading "*" of doc comment lines
+func docComment(comment string) string {
+	lines := strings.Split(strings.TrimPrefix(comment, "*"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,635 @@
+package avroidl
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+var (
+	primitiveTypes = map[string]bool{
+		"null": true, "boolean": true, "int": true, "long": true,
+		"float": true, "double": true, "bytes": true, "string": true,
+	}
+	// logicalTypes maps IDL shorthands of logical types to their underlying type and logical type name
+	logicalTypes = map[string][2]string{
+		"date":               {"int", "date"},
+		"time_ms":            {"int", "time-millis"},
+		"timestamp_ms":       {"long", "timestamp-millis"},
+		"local_timestamp_ms": {"long", "local-timestamp-millis"},
+		"uuid":               {"string", "uuid"},
+	}
+)
+
+// schemaType is type expression of IDL: primitive, reference to named type, array, map or union
+type schemaType struct {
+	tok      token
+	kind     string
+	name     string
+	items    *schemaType
+	branches []*schemaType
+	props    object
+	// namespace unqualified references are resolved in
+	namespace string
+}
+
+// namedSchema is record, error, enum or fixed declared in IDL (or imported from JSON schema or protocol)
+type namedSchema struct {
+	tok         token
+	kind        string
+	name        string
+	namespace   string
+	doc         string
+	props       object
+	fields      []*field
+	symbols     []string
+	enumDefault string
+	size        json.Number
+	// raw is JSON definition of type imported from schema or protocol, nested names are defined along with it
+	raw    interface{}
+	nested []string
+}
+
+func (s *namedSchema) fullName() string {
+	return qualify(s.namespace, s.name)
+}
+
+type field struct {
+	tok          token
+	name         string
+	doc          string
+	typ          *schemaType
+	defaultValue interface{}
+	hasDefault   bool
+	// defaultTok is the token default value starts at
+	defaultTok token
+	props      object
+}
+
+type importDecl struct {
+	tok  token
+	kind string
+	path string
+}
+
+// parsedFile is IDL file parsed into named types, imports and (optional) main schema
+type parsedFile struct {
+	namespace string
+	named     []*namedSchema
+	imports   []importDecl
+	main      *schemaType
+}
+
+type parser struct {
+	lexer  *lexer
+	tokens []token
+	pos    int
+	file   parsedFile
+}
+
+// parse parses IDL file in either protocol or schema syntax
+func parse(file string, src string) (*parsedFile, error) {
+	l := newLexer(file, src)
+	tokens, err := l.tokens()
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{lexer: l, tokens: tokens}
+	if err := p.parseFile(); err != nil {
+		return nil, err
+	}
+	return &p.file, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	return p.lexer.errorf(tok.line, tok.column, format, args...)
+}
+
+func (p *parser) expect(text string) (token, error) {
+	tok := p.next()
+	if !tok.is(text) {
+		return tok, p.errorf(tok, "expected %q, got %s", text, tok)
+	}
+	return tok, nil
+}
+
+func (p *parser) expectIdent(what string) (token, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return tok, p.errorf(tok, "expected %s, got %s", what, tok)
+	}
+	return tok, nil
+}
+
+func (p *parser) parseFile() error {
+	if p.peek().is("namespace") {
+		p.next()
+		namespace, err := p.expectIdent("namespace")
+		if err != nil {
+			return err
+		}
+		p.file.namespace = namespace.text
+		if _, err := p.expect(";"); err != nil {
+			return err
+		}
+	}
+	if p.peek().is("schema") {
+		p.next()
+		main, err := p.parseFullType()
+		if err != nil {
+			return err
+		}
+		p.file.main = main
+		if _, err := p.expect(";"); err != nil {
+			return err
+		}
+	}
+	for p.peek().kind != tokenEOF {
+		start := p.peek()
+		annotations, err := p.parseAnnotations()
+		if err != nil {
+			return err
+		}
+		if p.peek().is("protocol") {
+			if len(p.file.named) > 0 || len(p.file.imports) > 0 || p.file.main != nil {
+				return p.errorf(p.peek(), "protocol has to be the only declaration of the file")
+			}
+			return p.parseProtocol(annotations)
+		}
+		if err := p.parseDeclaration(start, annotations, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseProtocol(annotations object) error {
+	p.next()
+	if namespace, ok := annotations.get("namespace"); ok {
+		namespaceString, ok := namespace.(string)
+		if !ok {
+			return p.errorf(p.peek(), "@namespace requires string value")
+		}
+		p.file.namespace = namespaceString
+	}
+	if _, err := p.expectIdent("protocol name"); err != nil {
+		return err
+	}
+	if _, err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek().is("}") {
+		if p.peek().kind == tokenEOF {
+			return p.errorf(p.peek(), "expected \"}\" closing protocol, got %s", p.peek())
+		}
+		start := p.peek()
+		annotations, err := p.parseAnnotations()
+		if err != nil {
+			return err
+		}
+		if err := p.parseDeclaration(start, annotations, true); err != nil {
+			return err
+		}
+	}
+	p.next()
+	if tok := p.next(); tok.kind != tokenEOF {
+		return p.errorf(tok, "unexpected %s after protocol", tok)
+	}
+	return nil
+}
+
+// parseDeclaration parses import, named type or (within protocol) message, which is ignored
+func (p *parser) parseDeclaration(start token, annotations object, inProtocol bool) error {
+	tok := p.peek()
+	switch {
+	case tok.is("import"):
+		if len(annotations) > 0 {
+			return p.errorf(tok, "imports can't be annotated")
+		}
+		return p.parseImport()
+	case tok.is("record"), tok.is("error"), tok.is("enum"), tok.is("fixed"):
+		named, err := p.parseNamed(annotations)
+		if err != nil {
+			return err
+		}
+		named.doc = start.doc
+		p.file.named = append(p.file.named, named)
+		return nil
+	case inProtocol:
+		return p.parseMessage()
+	}
+	return p.errorf(tok, "expected record, error, enum, fixed or import, got %s", tok)
+}
+
+func (p *parser) parseImport() error {
+	p.next()
+	kind := p.next()
+	if !kind.is("idl") && !kind.is("protocol") && !kind.is("schema") {
+		return p.errorf(kind, "expected import kind (idl, protocol or schema), got %s", kind)
+	}
+	path := p.next()
+	if path.kind != tokenString {
+		return p.errorf(path, "expected import path, got %s", path)
+	}
+	p.file.imports = append(p.file.imports, importDecl{tok: path, kind: kind.text, path: path.text})
+	_, err := p.expect(";")
+	return err
+}
+
+func (p *parser) parseNamed(annotations object) (*namedSchema, error) {
+	kind := p.next()
+	nameTok, err := p.expectIdent(kind.text + " name")
+	if err != nil {
+		return nil, err
+	}
+	named := &namedSchema{tok: nameTok, kind: kind.text}
+	named.namespace, named.name = splitName(nameTok.text, p.file.namespace)
+	for _, annotation := range annotations {
+		if annotation.Key != "namespace" {
+			named.props = append(named.props, annotation)
+			continue
+		}
+		namespace, ok := annotation.Value.(string)
+		if !ok {
+			return nil, p.errorf(nameTok, "@namespace requires string value")
+		}
+		if !strings.Contains(nameTok.text, ".") {
+			named.namespace = namespace
+		}
+	}
+
+	switch kind.text {
+	case "record", "error":
+		err = p.parseFields(named)
+	case "enum":
+		err = p.parseEnum(named)
+	case "fixed":
+		err = p.parseFixed(named)
+	}
+	return named, err
+}
+
+func (p *parser) parseFields(named *namedSchema) error {
+	if _, err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek().is("}") {
+		if p.peek().kind == tokenEOF {
+			return p.errorf(p.peek(), "expected \"}\" closing %s %s, got %s", named.kind, named.name, p.peek())
+		}
+		start := p.peek()
+		typ, err := p.parseFullType()
+		if err != nil {
+			return err
+		}
+		typ.setNamespace(named.namespace)
+		for {
+			f, err := p.parseVariable(typ)
+			if err != nil {
+				return err
+			}
+			if len(f.doc) == 0 {
+				f.doc = start.doc
+			}
+			named.fields = append(named.fields, f)
+			if !p.peek().is(",") {
+				break
+			}
+			p.next()
+		}
+		if _, err := p.expect(";"); err != nil {
+			return err
+		}
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parseVariable(typ *schemaType) (*field, error) {
+	start := p.peek()
+	annotations, err := p.parseAnnotations()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.expectIdent("field name")
+	if err != nil {
+		return nil, err
+	}
+	f := &field{tok: name, name: name.text, doc: start.doc, typ: typ, props: annotations}
+	if p.peek().is("=") {
+		p.next()
+		f.defaultTok = p.peek()
+		f.defaultValue, err = p.parseJSONValue()
+		if err != nil {
+			return nil, err
+		}
+		f.hasDefault = true
+	}
+	return f, nil
+}
+
+func (p *parser) parseEnum(named *namedSchema) error {
+	if _, err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.peek().is("}") {
+		symbol, err := p.expectIdent("enum symbol")
+		if err != nil {
+			return err
+		}
+		named.symbols = append(named.symbols, symbol.text)
+		if !p.peek().is(",") {
+			break
+		}
+		p.next()
+	}
+	if _, err := p.expect("}"); err != nil {
+		return err
+	}
+	if p.peek().is("=") {
+		p.next()
+		symbol, err := p.expectIdent("enum default symbol")
+		if err != nil {
+			return err
+		}
+		named.enumDefault = symbol.text
+		if _, err := p.expect(";"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseFixed(named *namedSchema) error {
+	if _, err := p.expect("("); err != nil {
+		return err
+	}
+	size := p.next()
+	if size.kind != tokenNumber || strings.ContainsAny(size.text, "-.eE") {
+		return p.errorf(size, "expected fixed size, got %s", size)
+	}
+	named.size = json.Number(size.text)
+	if _, err := p.expect(")"); err != nil {
+		return err
+	}
+	_, err := p.expect(";")
+	return err
+}
+
+// parseMessage parses (and drops) protocol message, it has no meaning for schema registry
+func (p *parser) parseMessage() error {
+	if p.peek().is("void") {
+		p.next()
+	} else if _, err := p.parseFullType(); err != nil {
+		return err
+	}
+	if _, err := p.expectIdent("message name"); err != nil {
+		return err
+	}
+	if _, err := p.expect("("); err != nil {
+		return err
+	}
+	for !p.peek().is(")") {
+		typ, err := p.parseFullType()
+		if err != nil {
+			return err
+		}
+		if _, err := p.parseVariable(typ); err != nil {
+			return err
+		}
+		if !p.peek().is(",") {
+			break
+		}
+		p.next()
+	}
+	if _, err := p.expect(")"); err != nil {
+		return err
+	}
+	if p.peek().is("oneway") {
+		p.next()
+	} else if p.peek().is("throws") {
+		for {
+			p.next()
+			if _, err := p.expectIdent("error name"); err != nil {
+				return err
+			}
+			if !p.peek().is(",") {
+				break
+			}
+		}
+	}
+	_, err := p.expect(";")
+	return err
+}
+
+// parseFullType parses annotated type, optionally nullable (with "?" suffix)
+func (p *parser) parseFullType() (*schemaType, error) {
+	annotations, err := p.parseAnnotations()
+	if err != nil {
+		return nil, err
+	}
+	typ, err := p.parsePlainType()
+	if err != nil {
+		return nil, err
+	}
+	if len(annotations) > 0 {
+		if typ.kind == "union" || typ.kind == "reference" {
+			return nil, p.errorf(typ.tok, "%s can't be annotated", typ.kind)
+		}
+		typ.props = append(typ.props, annotations...)
+	}
+	if p.peek().is("?") {
+		tok := p.next()
+		typ = &schemaType{tok: tok, kind: "nullable", branches: []*schemaType{
+			{tok: tok, kind: "primitive", name: "null"}, typ,
+		}}
+	}
+	return typ, nil
+}
+
+func (p *parser) parsePlainType() (*schemaType, error) {
+	tok, err := p.expectIdent("type")
+	if err != nil {
+		return nil, err
+	}
+	typ := &schemaType{tok: tok, namespace: p.file.namespace}
+	switch {
+	case tok.is("array"), tok.is("map"):
+		typ.kind = tok.text
+		if _, err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		if typ.items, err = p.parseFullType(); err != nil {
+			return nil, err
+		}
+		_, err = p.expect(">")
+		return typ, err
+	case tok.is("union"):
+		typ.kind = "union"
+		if _, err := p.expect("{"); err != nil {
+			return nil, err
+		}
+		for {
+			branch, err := p.parseFullType()
+			if err != nil {
+				return nil, err
+			}
+			typ.branches = append(typ.branches, branch)
+			if !p.peek().is(",") {
+				break
+			}
+			p.next()
+		}
+		_, err = p.expect("}")
+		return typ, err
+	case tok.is("decimal"):
+		return p.parseDecimal(typ)
+	case !tok.quoted && primitiveTypes[tok.text]:
+		typ.kind = "primitive"
+		typ.name = tok.text
+	case !tok.quoted && len(logicalTypes[tok.text][0]) > 0:
+		typ.kind = "primitive"
+		typ.name = logicalTypes[tok.text][0]
+		typ.props = object{{"logicalType", logicalTypes[tok.text][1]}}
+	default:
+		typ.kind = "reference"
+		typ.name = tok.text
+	}
+	return typ, nil
+}
+
+func (p *parser) parseDecimal(typ *schemaType) (*schemaType, error) {
+	typ.kind = "primitive"
+	typ.name = "bytes"
+	if _, err := p.expect("("); err != nil {
+		return nil, err
+	}
+	precision := p.next()
+	if precision.kind != tokenNumber {
+		return nil, p.errorf(precision, "expected decimal precision, got %s", precision)
+	}
+	if _, err := p.expect(","); err != nil {
+		return nil, err
+	}
+	scale := p.next()
+	if scale.kind != tokenNumber {
+		return nil, p.errorf(scale, "expected decimal scale, got %s", scale)
+	}
+	typ.props = object{
+		{"logicalType", "decimal"},
+		{"precision", json.Number(precision.text)},
+		{"scale", json.Number(scale.text)},
+	}
+	_, err := p.expect(")")
+	return typ, err
+}
+
+// parseAnnotations parses schema properties, e.g. @namespace("org.example") or @aliases(["Old"])
+func (p *parser) parseAnnotations() (object, error) {
+	var annotations object
+	for p.peek().kind == tokenAnnotation {
+		name := p.next()
+		if _, err := p.expect("("); err != nil {
+			return nil, err
+		}
+		value, err := p.parseJSONValue()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, member{name.text, value})
+	}
+	return annotations, nil
+}
+
+// parseJSONValue parses JSON literal of default value or annotation
+func (p *parser) parseJSONValue() (interface{}, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == tokenString:
+		return tok.text, nil
+	case tok.kind == tokenNumber:
+		return json.Number(tok.text), nil
+	case tok.is("true"), tok.is("false"):
+		return tok.text == "true", nil
+	case tok.is("null"):
+		return nil, nil
+	case tok.is("["):
+		values := []interface{}{}
+		for !p.peek().is("]") {
+			value, err := p.parseJSONValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if !p.peek().is(",") {
+				break
+			}
+			p.next()
+		}
+		_, err := p.expect("]")
+		return values, err
+	case tok.is("{"):
+		values := object{}
+		for !p.peek().is("}") {
+			key := p.next()
+			if key.kind != tokenString {
+				return nil, p.errorf(key, "expected JSON object key, got %s", key)
+			}
+			if _, err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			value, err := p.parseJSONValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, member{key.text, value})
+			if !p.peek().is(",") {
+				break
+			}
+			p.next()
+		}
+		_, err := p.expect("}")
+		return values, err
+	}
+	return nil, p.errorf(tok, "expected JSON value, got %s", tok)
+}
+
+// setNamespace sets namespace unqualified references of the type (and its nested types) are resolved in
+func (t *schemaType) setNamespace(namespace string) {
+	t.namespace = namespace
+	if t.items != nil {
+		t.items.setNamespace(namespace)
+	}
+	for _, branch := range t.branches {
+		branch.setNamespace(namespace)
+	}
+}
+
+// splitName splits (possibly qualified) name into namespace and simple name
+func splitName(name string, namespace string) (string, string) {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return namespace, name
+}
+
+func qualify(namespace string, name string) string {
+	if len(namespace) == 0 {
+		return name
+	}
+	return namespace + "." + name
+}
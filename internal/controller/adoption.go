@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const adoptedVersionAnnotation = "kafka.incubly.oss/adopted-version"
+
+/*
+needsAdoption tells if the resource is about to register its subject for the first time
+(i.e. it was never reconciled successfully, never registered any schema and never adopted any subject)
+*/
+func needsAdoption(res *v1beta1.KafkaSchema) bool {
+	return res.Status.ObservedGeneration == 0 &&
+		res.Status.SchemaId == 0 &&
+		res.Status.Adoption == nil
+}
+
+/*
+adoptSubject checks if the subject already exists in schema registry and decides
+(according to AdoptionMode) if the resource may take it over.
+Subject restored by the resource itself (see RestoreSoftDeleted) is adopted unconditionally.
+Returns nil (without error) if there's nothing to adopt
+*/
+func adoptSubject(
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient,
+	registerReq schemareg.RegisterSchemaReq,
+	restored bool) (*v1beta1.Adoption, error) {
+
+	subjectName := res.Status.Subject
+	latest, err := srClient.GetLatestSchema(subjectName)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	compatibility, err := srClient.GetCompatibilityMode(subjectName)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := getAdoptionMode(res)
+	if restored {
+		mode = v1beta1.AdoptAndUpdate
+	}
+	switch mode {
+	case v1beta1.AdoptionFail:
+		return nil, fmt.Errorf("subject %s already exists (latest version: %d) and adoption mode is %s",
+			subjectName, latest.Version, mode)
+	case v1beta1.AdoptIfMatches:
+		registered, err := srClient.LookupSchema(subjectName, registerReq)
+		if err != nil {
+			return nil, err
+		}
+		if registered == nil || registered.Version != latest.Version {
+			return nil, fmt.Errorf("latest version %d of subject %s doesn't match the resource",
+				latest.Version, subjectName)
+		}
+		actualConfig, err := srClient.GetSubjectConfig(subjectName)
+		if err != nil {
+			return nil, err
+		}
+		if configDiff, _ := diffSubjectConfig(desiredSubjectConfig(res), actualConfig); len(configDiff) > 0 {
+			return nil, fmt.Errorf("subject config (%s) of subject %s doesn't match the resource",
+				strings.Join(configDiff, ", "), subjectName)
+		}
+	case v1beta1.AdoptAndUpdate:
+	default:
+		// adopt unconditionally
+	}
+
+	return &v1beta1.Adoption{
+		Version:       latest.Version,
+		SchemaId:      latest.Id,
+		Compatibility: compatibility,
+		AdoptedAt:     metav1.Now(),
+	}, nil
+}
+
+func getAdoptionMode(schema *v1beta1.KafkaSchema) v1beta1.AdoptionMode {
+	resourceMode := schema.Spec.Adoption
+	if len(resourceMode) > 0 {
+		return resourceMode
+	}
+	defaultAdoptionMode := os.Getenv("DEFAULT_ADOPTION_MODE")
+	if len(defaultAdoptionMode) > 0 {
+		return v1beta1.AdoptionMode(defaultAdoptionMode)
+	}
+	return v1beta1.AdoptAndUpdate
+}
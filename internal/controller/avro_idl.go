@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/avroidl"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+/*
+compileSchemaData compiles schema payload authored in .data.sourceFormat to .data.format,
+before it's normalized and registered. Compiled payload is never written back to the resource
+*/
+func (r *KafkaSchemaReconciler) compileSchemaData(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	data v1beta1.KafkaSchemaData) (v1beta1.KafkaSchemaData, error) {
+
+	switch data.SourceFormat {
+	case "":
+		return data, nil
+	case v1beta1.AVRO_IDL:
+		if data.Format != v1beta1.AVRO {
+			return data, fmt.Errorf("AVRO_IDL source format requires AVRO format")
+		}
+		var err error
+		data.Schema, err = avroidl.Compile(data.Schema, r.avroIdlImports(ctx, res))
+		return data, err
+	}
+	return data, fmt.Errorf("unsupported source format %s", data.SourceFormat)
+}
+
+/*
+avroIdlImports returns reader of files imported by Avro IDL from ConfigMaps of .data.importsFrom
+(followed by ConfigMap the schema is loaded from), looked up by import path first and by file name then
+*/
+func (r *KafkaSchemaReconciler) avroIdlImports(ctx context.Context, res *v1beta1.KafkaSchema) avroidl.ReadFile {
+	var configMaps []*corev1.ConfigMap
+	loaded := false
+	load := func() error {
+		if loaded {
+			return nil
+		}
+		for _, name := range avroIdlImportConfigMaps(res) {
+			configMap := &corev1.ConfigMap{}
+			if err := r.Get(ctx, types.NamespacedName{Namespace: res.Namespace, Name: name}, configMap); err != nil {
+				return fmt.Errorf("failed to read ConfigMap %s: %w", name, err)
+			}
+			configMaps = append(configMaps, configMap)
+		}
+		loaded = true
+		return nil
+	}
+
+	return func(filePath string) (string, error) {
+		if err := load(); err != nil {
+			return "", err
+		}
+		for _, key := range []string{filePath, path.Base(filePath)} {
+			for _, configMap := range configMaps {
+				if content, ok := configMap.Data[key]; ok {
+					return content, nil
+				}
+			}
+		}
+		return "", fmt.Errorf("%s isn't in ConfigMaps %v", filePath, avroIdlImportConfigMaps(res))
+	}
+}
+
+// avroIdlImportConfigMaps returns names of ConfigMaps files imported by Avro IDL are looked up in
+func avroIdlImportConfigMaps(res *v1beta1.KafkaSchema) []string {
+	var names []string
+	for _, ref := range res.Spec.Data.ImportsFrom {
+		names = append(names, ref.Name)
+	}
+	if source := res.Spec.Data.SchemaFrom; source != nil && source.ConfigMapKeyRef != nil {
+		names = append(names, source.ConfigMapKeyRef.Name)
+	}
+	return names
+}
@@ -1,7 +1,7 @@
 package controller
 
 import (
-	"os"
+	"sort"
 
 	"incubly.oss/kafka-schema-operator/api/v1beta1"
 	"incubly.oss/kafka-schema-operator/internal/schemareg"
@@ -20,6 +20,8 @@ func performCleanup(resource *v1beta1.KafkaSchema, srClient *schemareg.SrClient)
 		} else {
 			return srClient.DeleteSubject(subjectName, true)
 		}
+	case v1beta1.VERSIONS:
+		return deleteRegisteredVersions(resource, srClient)
 	case v1beta1.DISABLED:
 	default:
 		// do nothing
@@ -27,14 +29,74 @@ func performCleanup(resource *v1beta1.KafkaSchema, srClient *schemareg.SrClient)
 	return nil
 }
 
+/*
+deleteRegisteredVersions deletes only subject versions registered by the resource.
+Schema registry treats the subject as deleted once all its versions are deleted
+*/
+func deleteRegisteredVersions(resource *v1beta1.KafkaSchema, srClient *schemareg.SrClient) error {
+	subjectName := resource.Status.Subject
+	for _, version := range resource.Status.RegisteredVersions {
+		if err := srClient.DeleteSubjectVersion(subjectName, version, false); err != nil {
+			return err
+		}
+		if resource.Spec.PermanentVersionsCleanup {
+			if err := srClient.DeleteSubjectVersion(subjectName, version, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func getCleanupPolicy(schema *v1beta1.KafkaSchema) v1beta1.CleanupPolicy {
-	resourcePolicy := schema.Spec.CleanupPolicy
-	if len(resourcePolicy) > 0 {
-		return resourcePolicy
+	return schema.EffectiveCleanupPolicy()
+}
+
+/*
+findReferencingSubjects returns (sorted) names of other subjects whose schemas reference
+versions of the subject that are about to be deleted by cleanup
+*/
+func findReferencingSubjects(resource *v1beta1.KafkaSchema, srClient *schemareg.SrClient) ([]string, error) {
+	subjectName := resource.Status.Subject
+	versions := resource.Status.RegisteredVersions
+	if getCleanupPolicy(resource) != v1beta1.VERSIONS {
+		var err error
+		versions, err = srClient.ListVersions(subjectName, false)
+		if err != nil {
+			return nil, err
+		}
 	}
-	defaultCleanupPolicy := os.Getenv("DEFAULT_CLEANUP_POLICY")
-	if len(defaultCleanupPolicy) > 1 {
-		return v1beta1.CleanupPolicy(defaultCleanupPolicy)
+
+	referencing := map[string]bool{}
+	for _, version := range versions {
+		schemaIds, err := srClient.GetReferencedBy(subjectName, version)
+		if err != nil {
+			return nil, err
+		}
+		for _, schemaId := range schemaIds {
+			subjectVersions, err := srClient.GetSchemaVersions(schemaId, subjectName)
+			if err != nil {
+				return nil, err
+			}
+			for _, subjectVersion := range subjectVersions {
+				if subjectVersion.Subject != subjectName {
+					referencing[subjectVersion.Subject] = true
+				}
+			}
+		}
+	}
+
+	subjects := make([]string, 0, len(referencing))
+	for subject := range referencing {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+	return subjects, nil
+}
+
+func getCleanupOnReferenced(schema *v1beta1.KafkaSchema) v1beta1.CleanupOnReferenced {
+	if len(schema.Spec.CleanupOnReferenced) > 0 {
+		return schema.Spec.CleanupOnReferenced
 	}
-	return v1beta1.DISABLED
+	return v1beta1.ReferencedBlock
 }
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+)
+
+/*
+checkDrift detects drift of already reconciled resource and reflects it in "Drifted" condition.
+Returns detected differences (empty if registry is in sync or detection was skipped)
+*/
+func checkDrift(
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient,
+	registerReq schemareg.RegisterSchemaReq,
+	contentHash string) ([]string, error) {
+
+	if getDriftPolicy(res) == v1beta1.DriftIgnore {
+		res.RemoveCondition("Drifted")
+		return nil, nil
+	}
+	if res.Status.ObservedGeneration == 0 || res.Status.ObservedGeneration != res.Generation ||
+		res.Status.SchemaContentHash != contentHash {
+		// resource (or schema payload loaded from ConfigMap or Secret) has changed since last successful
+		// reconciliation, it's going to be applied anyway
+		return nil, nil
+	}
+	drift, err := detectDrift(res, srClient, registerReq)
+	if err != nil {
+		return nil, err
+	}
+	if len(drift) > 0 {
+		res.SetDriftedReason(v1beta1.DriftDetected, strings.Join(drift, "; "))
+	} else {
+		res.SetDriftedReason(v1beta1.InSync, "Schema registry is in sync with the resource")
+	}
+	return drift, nil
+}
+
+/*
+verifyDriftCorrected checks if previously detected drift was corrected by re-applying the resource.
+Some drift can't be corrected that way (e.g. newer version registered manually stays the latest one),
+so it remains reported in "Drifted" condition
+*/
+func verifyDriftCorrected(
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient,
+	registerReq schemareg.RegisterSchemaReq,
+	correctedDrift []string) error {
+
+	remainingDrift, err := detectDrift(res, srClient, registerReq)
+	if err != nil {
+		return err
+	}
+	if len(remainingDrift) > 0 {
+		res.SetDriftedReason(v1beta1.DriftDetected,
+			"Unable to correct drift: "+strings.Join(remainingDrift, "; "))
+	} else {
+		res.SetDriftedReason(v1beta1.DriftCorrected,
+			"Corrected drift: "+strings.Join(correctedDrift, "; "))
+	}
+	return nil
+}
+
+/*
+detectDrift compares state of the subject in schema registry with the resource.
+Returns human-readable descriptions of all detected differences (empty if registry is in sync)
+*/
+func detectDrift(
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient,
+	registerReq schemareg.RegisterSchemaReq) ([]string, error) {
+
+	subjectName := res.Status.Subject
+	var drift []string
+
+	latest, err := srClient.GetLatestSchema(subjectName)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		softDeleted, err := isSoftDeleted(srClient, subjectName)
+		if err != nil {
+			return nil, err
+		}
+		if softDeleted {
+			return append(drift, fmt.Sprintf("subject %s is soft-deleted", subjectName)), nil
+		} else {
+			return append(drift, fmt.Sprintf("subject %s not found", subjectName)), nil
+		}
+	}
+
+	// re-applied older schema (without rollback) leaves newer versions in place, they aren't drift
+	expectedLatest := max(res.Status.SchemaVersion, res.Status.LatestVersion)
+	if res.Status.SchemaVersion > 0 && latest.Version != expectedLatest {
+		drift = append(drift, fmt.Sprintf("latest version is %d, expected %d",
+			latest.Version, expectedLatest))
+	}
+
+	registered, err := srClient.LookupSchema(subjectName, registerReq)
+	if err != nil {
+		return nil, err
+	}
+	if expectedLatest == res.Status.SchemaVersion {
+		if registered == nil || registered.Id != latest.Id {
+			drift = append(drift, fmt.Sprintf("latest schema (id=%d) doesn't match the resource", latest.Id))
+		}
+	} else if registered == nil || registered.Version != res.Status.SchemaVersion {
+		drift = append(drift, fmt.Sprintf("schema version %d doesn't match the resource", res.Status.SchemaVersion))
+	}
+
+	desiredConfig := desiredSubjectConfig(res)
+	actualConfig, err := srClient.GetSubjectConfig(subjectName)
+	if err != nil {
+		return nil, err
+	}
+	configDiff, _ := diffSubjectConfig(desiredConfig, actualConfig)
+	for _, setting := range configDiff {
+		if setting == "compatibility" {
+			actualCompatibility := v1beta1.CompatibilityMode("")
+			if actualConfig != nil {
+				actualCompatibility = actualConfig.CompatibilityLevel
+			}
+			drift = append(drift, fmt.Sprintf("compatibility is %q, expected %q",
+				actualCompatibility, desiredConfig.Compatibility))
+		} else {
+			drift = append(drift, fmt.Sprintf("subject config %s doesn't match the resource", setting))
+		}
+	}
+	return drift, nil
+}
+
+func isSoftDeleted(srClient *schemareg.SrClient, subjectName string) (bool, error) {
+	subjects, err := srClient.ListSubjects(subjectName, true)
+	if err != nil {
+		return false, err
+	}
+	for _, subject := range subjects {
+		if subject == subjectName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func getDriftPolicy(schema *v1beta1.KafkaSchema) v1beta1.DriftPolicy {
+	resourcePolicy := schema.Spec.DriftPolicy
+	if len(resourcePolicy) > 0 {
+		return resourcePolicy
+	}
+	defaultDriftPolicy := os.Getenv("DEFAULT_DRIFT_POLICY")
+	if len(defaultDriftPolicy) > 0 {
+		return v1beta1.DriftPolicy(defaultDriftPolicy)
+	}
+	return v1beta1.DriftCorrect
+}
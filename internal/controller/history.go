@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+)
+
+/*
+replayVersions registers historical versions of the schema (.spec.versions) missing in the subject, in order.
+History is replayed against empty or lagging subject only: replay continues after the historical version
+which is the latest version of the subject, and nothing is replayed if the latest version isn't part of the history
+(e.g. it's .spec.data.schema already). Returns versions registered by the replay
+*/
+func (r *KafkaSchemaReconciler) replayVersions(
+	ctx context.Context,
+	subjectName string,
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient) ([]int, error) {
+
+	if len(res.Spec.Versions) == 0 {
+		return nil, nil
+	}
+	history, err := r.historicalRegisterReqs(ctx, res, srClient)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := srClient.GetLatestSchema(subjectName)
+	if err != nil {
+		return nil, err
+	}
+	start := 0
+	if latest != nil {
+		start = -1
+		for i, req := range history {
+			registered, err := srClient.LookupSchema(subjectName, req)
+			if err != nil {
+				return nil, err
+			}
+			if registered != nil && registered.Version == latest.Version {
+				start = i + 1
+			}
+		}
+		if start < 0 {
+			return nil, nil
+		}
+	}
+
+	var replayed []int
+	for _, req := range history[start:] {
+		if _, err := srClient.RegisterSchema(subjectName, req); errors.As(err, &schemareg.OperationNotPermitted{}) {
+			// read-only subject is reported when registering .spec.data.schema
+			return replayed, nil
+		} else if err != nil {
+			return replayed, err
+		}
+		registered, err := srClient.LookupSchema(subjectName, req)
+		if err != nil {
+			return replayed, err
+		}
+		if registered != nil {
+			replayed = append(replayed, registered.Version)
+		}
+	}
+	return replayed, nil
+}
+
+/*
+historicalRegisterReqs resolves payloads of historical versions (inline or from ConfigMaps).
+Historical payload replaces payload of .spec.data and goes through the same pipeline as the schema:
+it's compiled from its source format, normalized and registered with references (including imported Protobuf files),
+but without metadata and rule set
+*/
+func (r *KafkaSchemaReconciler) historicalRegisterReqs(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient) ([]schemareg.RegisterSchemaReq, error) {
+
+	var reqs []schemareg.RegisterSchemaReq
+	for i, version := range res.Spec.Versions {
+		data := res.Spec.Data
+		data.Schema = version.Schema
+		data.SchemaObject = nil
+		data.SchemaFrom = nil
+		if version.ConfigMapKeyRef != nil {
+			if len(version.Schema) > 0 {
+				return nil, fmt.Errorf("versions[%d]: schema and configMapKeyRef are mutually exclusive", i)
+			}
+			schema, err := r.readConfigMapKey(ctx, res.Namespace, version.ConfigMapKeyRef)
+			if err != nil {
+				return nil, fmt.Errorf("versions[%d]: %w", i, err)
+			}
+			data.Schema = schema
+		}
+		if len(data.Schema) == 0 {
+			return nil, fmt.Errorf("versions[%d]: either schema or configMapKeyRef is required", i)
+		}
+		data, err := r.compileSchemaData(ctx, res, data)
+		if err != nil {
+			return nil, fmt.Errorf("versions[%d]: %w", i, err)
+		}
+		schema, err := GetMaybeNormalizedSchema(data)
+		if err != nil {
+			return nil, fmt.Errorf("versions[%d]: %w", i, err)
+		}
+		references, err := r.schemaReferences(ctx, res, data, srClient)
+		if err != nil {
+			return nil, fmt.Errorf("versions[%d]: %w", i, err)
+		}
+		reqs = append(reqs, schemareg.RegisterSchemaReq{
+			Schema:     schema,
+			SchemaType: data.Format,
+			References: references,
+		})
+	}
+	return reqs, nil
+}
@@ -2,7 +2,10 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -12,11 +15,15 @@ import (
 
 	"github.com/go-logr/logr"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -24,16 +31,28 @@ import (
 type KafkaSchemaReconciler struct {
 	RequeueDelay         time.Duration
 	DefaultCleanupPolicy v1beta1.CleanupPolicy
+	// DeletionProtection tells which resources are protected from deletion (nil if it isn't enforced)
+	DeletionProtection *v1beta1.KafkaSchemaValidator
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=kafka.incubly.oss,resources=kafkaschemas,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=kafka.incubly.oss,resources=kafkaschemas/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=kafka.incubly.oss,resources=kafkaschemas/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 const finalizer = "kafka.incubly.oss/finalizer"
 
+// skipCleanupAnnotation (set to "true") makes controller release the finalizer without schema registry cleanup
+const skipCleanupAnnotation = "kafka.incubly.oss/skip-cleanup"
+
+// pausedAnnotation (set to "true") makes controller leave the resource and its subject untouched
+const pausedAnnotation = "kafka.incubly.oss/paused"
+
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 // This controller will try to apply state of KafkaSchema resources on
@@ -41,8 +60,12 @@ const finalizer = "kafka.incubly.oss/finalizer"
 // It is unidirectional (i.e. it only synchronizes state from Kube to Registry,
 // without trying to create KafkaSchema resources for discovered Registry
 // schemas and subjects that don't have their representation in Kube).
+// Importing subjects as KafkaSchema resources is opt-in and handled
+// separately by SchemaRegistryImportReconciler.
 //
 // During reconciliation, it will synchronize subject, schema and compatibility mode.
+// On each subsequent (requeued) reconciliation of unchanged resource, it will also
+// check if subject was modified in the registry directly and react according to DriftPolicy.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
@@ -68,12 +91,53 @@ func (r *KafkaSchemaReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		"resource::uid", res.UID,
 	)
 
+	if !res.GetDeletionTimestamp().IsZero() && res.Annotations[skipCleanupAnnotation] == "true" {
+		msg := fmt.Sprintf("Schema registry cleanup skipped (%s annotation)", skipCleanupAnnotation)
+		logger.Info(msg)
+		r.Recorder.Event(res, corev1.EventTypeWarning, "CleanupSkipped", msg)
+		return r.releaseFinalizer(ctx, res, logger)
+	}
+
+	if res.Annotations[pausedAnnotation] == "true" {
+		if res.SetCondition("Paused", v1beta1.Paused,
+			fmt.Sprintf("Reconciliation paused (%s annotation)", pausedAnnotation)) {
+			if err := r.Status().Update(ctx, res); err != nil {
+				logger.Error(err, "failed to update resource status")
+				return ctrl.Result{}, err
+			}
+		}
+		logger.Info("KafkaSchema CR reconciliation paused")
+		return r.requeue(), nil
+	}
+	res.RemoveCondition("Paused")
+
 	if res.SetReadyReason(v1beta1.InProgress, "Reconciliation in progress") {
 		// ignoring potential error, it's not critical here
 		_ = r.Status().Update(ctx, res)
 	}
 
+	deleting := !res.GetDeletionTimestamp().IsZero()
+	// cleanup of deleted resource relies on subject recorded in status, it doesn't need schema payload
+	// (which may be gone along with its ConfigMap, or may no longer compile)
+	registered := deleting && len(res.Status.Subject) > 0
+
 	spec := res.Spec
+	if !registered {
+		spec.Data, err = r.resolveSchemaData(ctx, res)
+		if err != nil && !deleting {
+			return r.logError(logger, err, ctx, res,
+				v1beta1.ResolveSchema,
+				"Failed to resolve schema payload")
+		}
+		if err == nil {
+			spec.Data, err = r.compileSchemaData(ctx, res, spec.Data)
+			if err != nil && !deleting {
+				return r.logError(logger, err, ctx, res,
+					v1beta1.CompileSchema,
+					fmt.Sprintf("Failed to compile schema: %s", err))
+			}
+		}
+	}
 
 	srClient, err := schemareg.NewClient(&spec.SchemaRegistry, logger)
 
@@ -83,14 +147,18 @@ func (r *KafkaSchemaReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			"Failed to instantiate Schema Registry Client")
 	}
 
-	subjectName, err := resolveSubjectName(&spec)
-	if err != nil {
-		return r.logError(logger, err, ctx, res,
-			v1beta1.NameStrategy,
-			"Failed to resolve subject name")
+	subjectName := res.Status.Subject
+	if !registered {
+		subjectName, err = resolveSubjectName(&spec)
+		if err != nil {
+			return r.logError(logger, err, ctx, res,
+				v1beta1.NameStrategy,
+				"Failed to resolve subject name")
+		}
+		subjectName = qualifySubject(subjectName, getSchemaContext(res))
 	}
 
-	if res.GetDeletionTimestamp().IsZero() {
+	if !deleting {
 		res.Status.SchemaRegistryUrl = srClient.BaseUrl.String()
 		res.Status.Subject = subjectName
 		err := r.Status().Update(ctx, res)
@@ -98,7 +166,17 @@ func (r *KafkaSchemaReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			logger.Error(err, "failed to update resource status")
 			return ctrl.Result{}, err
 		}
-		return r.reconcileResource(ctx, res, srClient, logger)
+		expired, err := r.ttlExpired(ctx, res, logger)
+		if err != nil {
+			return r.logError(logger, err, ctx, res,
+				v1beta1.ResourceUpdate,
+				"Failed to check deletion protection of expired KafkaSchema CR")
+		}
+		if expired {
+			return r.deleteExpired(ctx, res, logger)
+		}
+		result, err := r.reconcileResource(ctx, res, spec.Data, srClient, logger)
+		return requeueBeforeExpiry(res, result), err
 	} else {
 		return r.deleteResource(ctx, res, srClient, logger)
 	}
@@ -107,11 +185,13 @@ func (r *KafkaSchemaReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 func (r *KafkaSchemaReconciler) reconcileResource(
 	ctx context.Context,
 	res *v1beta1.KafkaSchema,
+	data v1beta1.KafkaSchemaData,
 	srClient *schemareg.SrClient,
 	logger logr.Logger) (ctrl.Result, error) {
 
 	subjectName := res.Status.Subject
 	spec := res.Spec
+	spec.Data = data
 
 	if controllerutil.AddFinalizer(res, finalizer) {
 		err := r.Update(ctx, res)
@@ -130,35 +210,189 @@ func (r *KafkaSchemaReconciler) reconcileResource(
 			"Failed to normalize schema")
 	}
 
-	schemaId, err := srClient.RegisterSchema(
-		subjectName,
-		schemareg.RegisterSchemaReq{
-			Schema:     maybeNormalizedSchema,
-			SchemaType: spec.Data.Format,
-		},
-	)
+	err = validateRuleSet(spec.Data.RuleSet)
+	if err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.ValidateRuleSet,
+			"Invalid rule set")
+	}
+
+	references, err := r.schemaReferences(ctx, res, spec.Data, srClient)
+	if err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.RegisterImports,
+			"Failed to register imported Protobuf files")
+	}
+	contentHash := registeredContentHash(spec.Data.Schema, references)
+
+	registerReq := schemareg.RegisterSchemaReq{
+		Schema:     maybeNormalizedSchema,
+		SchemaType: spec.Data.Format,
+		Metadata:   schemaMetadata(res),
+		RuleSet:    spec.Data.RuleSet,
+		References: references,
+	}
+
+	if needsAdoption(res) {
+		restored, err := restoreSoftDeleted(res, srClient)
+		if err != nil {
+			return r.logError(logger, err, ctx, res,
+				v1beta1.Restore,
+				"Failed to restore soft-deleted subject")
+		}
+		if restored > 0 {
+			msg := fmt.Sprintf("Restored %d soft-deleted version(s) of subject %s", restored, subjectName)
+			logger.Info(msg)
+			r.Recorder.Event(res, corev1.EventTypeNormal, "SubjectRestored", msg)
+		}
+
+		adoption, err := adoptSubject(res, srClient, registerReq, restored > 0)
+		if err != nil {
+			return r.logError(logger, err, ctx, res,
+				v1beta1.Adopt,
+				"Failed to adopt pre-existing subject")
+		}
+		if adoption != nil {
+			err := r.patchAnnotations(ctx, res, func(annotations map[string]string) {
+				annotations[adoptedVersionAnnotation] = strconv.Itoa(adoption.Version)
+			})
+			if err != nil {
+				return r.logError(logger, err, ctx, res,
+					v1beta1.ResourceUpdate,
+					"Failed to annotate adopted KafkaSchema CR")
+			}
+			res.Status.Adoption = adoption
+			logger.Info(fmt.Sprintf("Adopted pre-existing subject %s (version %d)", subjectName, adoption.Version))
+		} else {
+			res.Status.SubjectCreated = true
+		}
+	}
+
+	drift, err := checkDrift(res, srClient, registerReq, contentHash)
+	if err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.DetectDrift,
+			"Failed to detect drift in schema registry")
+	}
+	if len(drift) > 0 && getDriftPolicy(res) == v1beta1.DriftReport {
+		logger.Info("Drift detected, leaving schema registry untouched: " + strings.Join(drift, "; "))
+		return r.reconcileSuccess(ctx, res, logger)
+	}
+
+	err = reconcileSubjectMode(subjectName, res, srClient)
+	if err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.SetSubjectMode,
+			"Failed to update subject mode")
+	}
+
+	previouslyRegistered, err := srClient.LookupSchema(subjectName, registerReq)
 	if err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.RegisterSchema,
+			"Failed to look up schema in registry")
+	}
+
+	newer, err := rollbackVersions(subjectName, res, srClient, previouslyRegistered)
+	if err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.Rollback,
+			"Failed to list versions of the subject")
+	}
+	if len(newer) > 0 {
+		if !rollbackApproved(res, previouslyRegistered.Version) {
+			return r.rollbackPending(ctx, res, logger, previouslyRegistered.Version, newer)
+		}
+		err = r.rollback(ctx, subjectName, res, srClient, logger, previouslyRegistered.Version, newer)
+		if err != nil {
+			return r.logError(logger, err, ctx, res,
+				v1beta1.Rollback,
+				"Failed to roll back the subject")
+		}
+	}
+
+	if previouslyRegistered == nil && !isReadOnlyMode(spec.SubjectMode) {
+		replayed, err := r.replayVersions(ctx, subjectName, res, srClient)
+		if len(replayed) > 0 {
+			res.Status.RegisteredVersions = append(res.Status.RegisteredVersions, replayed...)
+			msg := fmt.Sprintf("Replayed %d historical version(s) of subject %s", len(replayed), subjectName)
+			logger.Info(msg)
+			r.Recorder.Event(res, corev1.EventTypeNormal, "VersionsReplayed", msg)
+		}
+		if err != nil {
+			return r.logError(logger, err, ctx, res,
+				v1beta1.ReplayVersions,
+				"Failed to replay historical versions of the schema")
+		}
+	}
+
+	schemaId, blocked, err := registerUnlessReadOnly(subjectName, res, srClient, registerReq, previouslyRegistered)
+	if isPinnedIdMismatch(err) {
+		res.SetCondition("IdMismatch", v1beta1.IdConflict, err.Error())
+		return r.logError(logger, err, ctx, res,
+			v1beta1.PinnedId,
+			"Schema registry doesn't match pinned schema id or version")
+	} else if err != nil {
 		return r.logError(logger, err, ctx, res,
 			v1beta1.RegisterSchema,
 			"Failed to register schema in registry")
 	}
+	if blocked {
+		return r.registrationBlocked(ctx, res, logger)
+	}
+	res.RemoveCondition("ReadOnly")
+	res.RemoveCondition("IdMismatch")
 	res.Status.SchemaId = schemaId
 
-	compatibility := spec.Data.Compatibility
-	if len(compatibility) > 0 {
-		err = srClient.SetCompatibilityMode(
-			subjectName,
-			schemareg.SetCompatibilityModeReq{
-				Compatibility: compatibility,
-			})
+	registered, err := srClient.LookupSchema(subjectName, registerReq)
+	if err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.RegisterSchema,
+			"Failed to look up registered schema version")
+	}
+	latest, err := srClient.GetLatestSchema(subjectName)
+	if err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.RegisterSchema,
+			"Failed to look up latest version of the subject")
+	}
+	if latest != nil && (res.Status.ObservedGeneration != res.Generation || res.Status.SchemaContentHash != contentHash) {
+		// recorded only when the resource is applied, correcting drift mustn't accept manually registered versions
+		res.Status.LatestVersion = latest.Version
+	}
+	if registered != nil {
+		res.Status.SchemaVersion = registered.Version
+		if previouslyRegistered == nil && !slices.Contains(res.Status.RegisteredVersions, registered.Version) {
+			res.Status.RegisteredVersions = append(res.Status.RegisteredVersions, registered.Version)
+		}
+	}
+
+	err = reconcileSubjectConfig(subjectName, res, srClient)
+	if err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.SetCompatibilityMode,
+			"Failed to update subject config")
+	}
 
+	if len(drift) > 0 {
+		err = verifyDriftCorrected(res, srClient, registerReq, drift)
 		if err != nil {
 			return r.logError(logger, err, ctx, res,
-				v1beta1.SetCompatibilityMode,
-				"Failed to update schema compatibility mode")
+				v1beta1.DetectDrift,
+				"Failed to detect drift in schema registry")
 		}
 	}
 
+	res.Status.ObservedGeneration = res.Generation
+	res.Status.SchemaContentHash = contentHash
+	return r.reconcileSuccess(ctx, res, logger)
+}
+
+func (r *KafkaSchemaReconciler) reconcileSuccess(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	logger logr.Logger) (ctrl.Result, error) {
+
 	res.SetReadyReason(v1beta1.Complete, "Reconciliation complete")
 	res.Status.Healthy = true
 	res.Status.RetryCount = 0
@@ -176,12 +410,16 @@ func (r *KafkaSchemaReconciler) reconcileResource(
 	}
 
 	logger.Info("KafkaSchema CR successfully reconciled")
+	return r.requeue(), nil
+}
+
+func (r *KafkaSchemaReconciler) requeue() ctrl.Result {
 	/*
 		delay<0 - don't requeue (Requeue: false)
 		delay=0 - exponential backoff (Requeue: true, RequeueAfter: 0)
 		delay>0 - static interval (Requeue: true, RequeueAfter should be respected)
 	*/
-	return ctrl.Result{Requeue: r.RequeueDelay >= 0, RequeueAfter: r.RequeueDelay}, nil
+	return ctrl.Result{Requeue: r.RequeueDelay >= 0, RequeueAfter: r.RequeueDelay}
 }
 
 func (r *KafkaSchemaReconciler) deleteResource(
@@ -190,17 +428,60 @@ func (r *KafkaSchemaReconciler) deleteResource(
 	srClient *schemareg.SrClient,
 	logger logr.Logger) (ctrl.Result, error) {
 
+	remaining, err := r.scheduleCleanup(ctx, res, logger)
+	if err != nil {
+		logger.Error(err, "failed to update resource status")
+		return ctrl.Result{}, err
+	}
+	if remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	if getCleanupPolicy(res) != v1beta1.DISABLED && getCleanupOnReferenced(res) != v1beta1.ReferencedForce {
+		referencing, err := findReferencingSubjects(res, srClient)
+		if err != nil {
+			return r.logError(logger, err, ctx, res,
+				v1beta1.Cleanup,
+				"Failed to check references to subject")
+		}
+		if len(referencing) > 0 {
+			msg := "Subject is referenced by: " + strings.Join(referencing, ", ")
+			if getCleanupOnReferenced(res) == v1beta1.ReferencedSkipAndRelease {
+				logger.Info(msg + ". Skipping schema registry cleanup")
+				return r.releaseFinalizer(ctx, res, logger)
+			}
+			res.SetCondition("ReferencedBy", v1beta1.Referenced, msg)
+			return r.logError(logger, fmt.Errorf("%s", msg), ctx, res,
+				v1beta1.Cleanup,
+				"Schema registry cleanup blocked by references")
+		}
+	}
+
 	// deleting / cleaning up resource
-	err := performCleanup(res, srClient)
+	err = performCleanup(res, srClient)
 	if err != nil {
 		return r.logError(logger, err, ctx, res,
 			v1beta1.Cleanup,
 			"Failed to perform schema registry cleanup")
 	}
 
+	return r.releaseFinalizer(ctx, res, logger)
+}
+
+func (r *KafkaSchemaReconciler) releaseFinalizer(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	logger logr.Logger) (ctrl.Result, error) {
+
+	if err := releaseInventorySubject(ctx, r.Client, res); err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.Cleanup,
+			"Failed to release subject in schema registry inventory")
+	}
+
 	// deleting CR
 	controllerutil.RemoveFinalizer(res, finalizer)
-	err = r.Update(ctx, res)
+	err := r.Update(ctx, res)
 	if err != nil {
 		return r.logError(logger, err, ctx, res,
 			v1beta1.Cleanup,
@@ -212,6 +493,28 @@ func (r *KafkaSchemaReconciler) deleteResource(
 	return ctrl.Result{}, nil
 }
 
+/*
+patchAnnotations patches annotations of the resource (metadata only), so that status set in memory
+during reconciliation isn't overwritten by the one stored in cluster
+*/
+func (r *KafkaSchemaReconciler) patchAnnotations(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	mutate func(annotations map[string]string)) error {
+
+	patched := res.DeepCopy()
+	if patched.Annotations == nil {
+		patched.Annotations = map[string]string{}
+	}
+	mutate(patched.Annotations)
+	if err := r.Patch(ctx, patched, client.MergeFrom(res)); err != nil {
+		return err
+	}
+	res.Annotations = patched.Annotations
+	res.ResourceVersion = patched.ResourceVersion
+	return nil
+}
+
 func (r *KafkaSchemaReconciler) logError(
 	logger logr.Logger,
 	err error,
@@ -237,8 +540,14 @@ func (r *KafkaSchemaReconciler) logError(
 // SetupWithManager sets up the controller with the Manager.
 func (r *KafkaSchemaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1beta1.KafkaSchema{}).
-		WithEventFilter(ignoreIfBeforeRequeueDelay(r.getStatus, r.RequeueDelay)).
+		For(&v1beta1.KafkaSchema{},
+			builder.WithPredicates(ignoreIfBeforeRequeueDelay(r.getStatus, r.RequeueDelay))).
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.schemasReferencing(referencedConfigMaps))).
+		// metadata of Secrets is enough to enqueue referencing schemas, content of referenced Secret is read directly
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.schemasReferencing(referencedSecrets)),
+			builder.OnlyMetadata).
 		Complete(r)
 }
 
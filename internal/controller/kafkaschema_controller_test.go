@@ -3,15 +3,22 @@ package controller
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
 	schemaregmock "incubly.oss/kafka-schema-operator/internal/schemareg-mock"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -22,6 +29,7 @@ var _ = Describe("KafkaSchema Controller", func() {
 
 	BeforeEach(func() {
 		srMock.Clear()
+		drainEvents()
 	})
 
 	ctx := context.Background()
@@ -200,6 +208,53 @@ var _ = Describe("KafkaSchema Controller", func() {
 			Expect(srMock.SoftDeletedSubjects).Should(HaveLen(1))
 			Expect(srMock.HardDeletedSubjects).Should(HaveLen(1))
 		})
+		It("Should delete only versions registered by resource if cleanup is VERSIONS", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.VERSIONS)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And another version was registered directly in registry")
+			srMock.RegisterSchema(aSchema.Spec.SubjectName, `"int"`)
+			By("And resource registered yet another version")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.Schema = `"long"`
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			Expect(aSchema.Status.RegisteredVersions).Should(Equal([]int{1, 3}))
+
+			By("When deleting the resource")
+			Ω(whenDeletingExistingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then only versions registered by resource should be soft-deleted")
+			Expect(srMock.Subjects).Should(HaveKey(aSchema.Spec.SubjectName))
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(subject.Versions()).Should(Equal([]int{2}))
+			Expect(subject.SoftDeletedVersions()).Should(ConsistOf(1, 3))
+		})
+		It("Should permanently delete versions registered by resource if requested", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.VERSIONS)
+			aSchema.Spec.PermanentVersionsCleanup = true
+			By("Given subject existed before resource was registered")
+			srMock.RegisterSchema(aSchema.Spec.SubjectName, `"int"`)
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When deleting the resource")
+			Ω(whenDeletingExistingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then only version registered by resource should be permanently deleted")
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(subject.Versions()).Should(Equal([]int{1}))
+			Expect(subject.SoftDeletedVersions()).Should(BeEmpty())
+		})
+		It("Should soft-delete subject if all its versions were registered by resource and cleanup is VERSIONS", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.VERSIONS)
+			By("When cleaning up schema with cleanup policy VERSIONS")
+			Ω(whenCreatingAndDeletingSchema(ctx, aSchema)).
+				ShouldNot(BeNil())
+
+			By("Then subject should be soft-deleted from registry")
+			Expect(srMock.Subjects).Should(BeEmpty())
+			Expect(srMock.SoftDeletedSubjects).Should(HaveLen(1))
+		})
 		It("Should handle errors correctly when trying to delete subject that doesn't exist", func() {
 			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
 			By("Given schema resource was registered")
@@ -213,95 +268,1602 @@ var _ = Describe("KafkaSchema Controller", func() {
 			Expect(err).Should(Succeed())
 		})
 	})
-	Context("Status", func() {
-		It("Should update status on successful reconciliation", func() {
-			By("When creating new schema")
-			aSchema := aSchemaWithNameStrategy(NameStrategy{
-				SubjectName: "test",
-				Format:      v1beta1.AVRO,
-				Schema:      `"string"`,
+	Context("Cleanup of referenced subjects", func() {
+		referenced := func(policy v1beta1.CleanupPolicy, onReferenced v1beta1.CleanupOnReferenced) *v1beta1.KafkaSchema {
+			aSchema := aSchemaWithCleanupPolicy(policy)
+			aSchema.Spec.CleanupOnReferenced = onReferenced
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And its schema is referenced by another subject")
+			srMock.AddReference("referencing", `"int"`, schemareg.SubjectVersion{
+				Subject: aSchema.Spec.SubjectName,
+				Version: 1,
+			})
+			return aSchema
+		}
+
+		It("Should block cleanup if subject is referenced", func() {
+			aSchema := referenced(v1beta1.HARD, "")
+			By("When deleting the resource")
+			_, err := whenDeletingExistingSchema(ctx, aSchema)
+
+			By("Then reconciliation should fail")
+			Expect(err).Should(HaveOccurred())
+
+			By("And subject should NOT be deleted from registry")
+			Expect(srMock.Subjects).Should(HaveKey(aSchema.Spec.SubjectName))
+
+			By("And resource should keep its finalizer and report referencing subjects")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			Expect(aSchema.Finalizers).Should(ContainElement(finalizer))
+			status := expectConditionWithReason(ctx, aSchema, "ReferencedBy", v1beta1.Referenced)
+			Expect(meta.FindStatusCondition(status.Conditions, "ReferencedBy").Message).
+				Should(ContainSubstring("referencing"))
+		})
+		It("Should block cleanup if subject is referenced within schema context", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.HARD)
+			aSchema.Spec.SchemaRegistry.Context = ".team-a"
+			qualifiedSubject := ":.team-a:" + aSchema.Spec.SubjectName
+			By("Given schema resource was registered in schema context")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And its schema is referenced by another subject of the schema context")
+			srMock.AddReference(":.team-a:referencing", `"int"`, schemareg.SubjectVersion{
+				Subject: qualifiedSubject,
+				Version: 1,
+			})
+
+			By("When deleting the resource")
+			_, err := whenDeletingExistingSchema(ctx, aSchema)
+
+			By("Then cleanup should be blocked")
+			Expect(err).Should(HaveOccurred())
+			Expect(srMock.Subjects).Should(HaveKey(qualifiedSubject))
+			status := expectConditionWithReason(ctx, aSchema, "ReferencedBy", v1beta1.Referenced)
+			Expect(meta.FindStatusCondition(status.Conditions, "ReferencedBy").Message).
+				Should(ContainSubstring(":.team-a:referencing"))
+		})
+		It("Should release finalizer without cleanup if subject is referenced and SkipAndRelease", func() {
+			aSchema := referenced(v1beta1.HARD, v1beta1.ReferencedSkipAndRelease)
+			By("When deleting the resource")
+			Ω(whenDeletingExistingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject should NOT be deleted from registry")
+			Expect(srMock.Subjects).Should(HaveKey(aSchema.Spec.SubjectName))
+
+			By("And resource should be deleted")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).ShouldNot(Succeed())
+		})
+		It("Should clean up regardless of references if Force", func() {
+			aSchema := referenced(v1beta1.SOFT, v1beta1.ReferencedForce)
+			By("When deleting the resource")
+			Ω(whenDeletingExistingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject should be soft-deleted from registry")
+			Expect(srMock.Subjects).ShouldNot(HaveKey(aSchema.Spec.SubjectName))
+			Expect(srMock.SoftDeletedSubjects).Should(HaveKey(aSchema.Spec.SubjectName))
+		})
+		It("Should ignore references to versions not deleted by VERSIONS cleanup", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.VERSIONS)
+			By("Given subject version existed before resource was registered")
+			srMock.RegisterSchema(aSchema.Spec.SubjectName, `"int"`)
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And pre-existing version is referenced by another subject")
+			srMock.AddReference("referencing", `"long"`, schemareg.SubjectVersion{
+				Subject: aSchema.Spec.SubjectName,
+				Version: 1,
+			})
+
+			By("When deleting the resource")
+			Ω(whenDeletingExistingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then version registered by resource should be deleted")
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].Versions()).Should(Equal([]int{1}))
+		})
+	})
+	Context("Annotations", func() {
+		It("Should release finalizer without cleanup if skip-cleanup annotation set", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.HARD)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And schema registry doesn't respond to deletion")
+			srMock.InjectError(schemaregmock.InjectedError{
+				OnApi:      schemaregmock.DeleteSubject,
+				StatusCode: 500,
+			})
+			By("And resource is annotated with skip-cleanup")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Annotations = map[string]string{skipCleanupAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+
+			By("When deleting the resource")
+			Ω(whenDeletingExistingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject should NOT be deleted from registry")
+			Expect(srMock.Subjects).Should(HaveKey(aSchema.Spec.SubjectName))
+			By("And resource should be deleted")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).ShouldNot(Succeed())
+			By("And skipped cleanup should be recorded")
+			Expect(events.Events).Should(Receive(ContainSubstring("CleanupSkipped")))
+		})
+		It("Should leave subject untouched while paused annotation set", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.DISABLED)
+			aSchema.Annotations = map[string]string{pausedAnnotation: "true"}
+			By("When creating paused schema")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject shouldn't be registered")
+			Expect(srMock.Subjects).Should(BeEmpty())
+			By("And resource should be marked as paused")
+			expectConditionWithReason(ctx, aSchema, "Paused", v1beta1.Paused)
+
+			By("When removing paused annotation")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			delete(aSchema.Annotations, pausedAnnotation)
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject should be registered")
+			Expect(srMock.Subjects).Should(HaveKey(aSchema.Spec.SubjectName))
+			By("And resource should no longer be marked as paused")
+			Expect(meta.FindStatusCondition(aSchema.Status.Conditions, "Paused")).Should(BeNil())
+		})
+	})
+	Context("Status", func() {
+		It("Should update status on successful reconciliation", func() {
+			By("When creating new schema")
+			aSchema := aSchemaWithNameStrategy(NameStrategy{
+				SubjectName: "test",
+				Format:      v1beta1.AVRO,
+				Schema:      `"string"`,
+			})
+
+			Ω(whenCreatingSchema(ctx, aSchema)).
+				ShouldNot(BeNil())
+
+			By("Then resource status should be set")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaRegistryUrl).To(Equal(srMockServer.URL()))
+			Expect(status.Subject).To(Equal(aSchema.Spec.SubjectName))
+			Expect(status.SchemaId).To(BeNumerically(">", 0))
+			Expect(status.Conditions).ToNot(BeEmpty())
+
+			By("And status matches schema registry entries")
+			Expect(srMock.Subjects).To(HaveKey(status.Subject))
+			Expect(srMock.Schemas).To(HaveKeyWithValue(status.SchemaId, aSchema.Spec.Data.Schema))
+		})
+		It("Should update status on failed NamingStrategy", func() {
+			By("When trying to create resource with invalid name strategy")
+			aSchema := aSchemaWithNameStrategy(NameStrategy{
+				Format: v1beta1.AVRO,
+				Schema: `"string"`,
+			})
+			_, err := whenCreatingSchema(ctx, aSchema)
+
+			By("And reconciliation fails")
+			Expect(err).ToNot(Succeed())
+
+			By("Then failed ready condition should be set")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.NameStrategy)
+
+		})
+		It("Should update status on failed Cleanup", func() {
+			By("Given DeleteSubject will fail in schema registry")
+			srMock.InjectError(schemaregmock.InjectedError{
+				OnApi:      schemaregmock.DeleteSubject,
+				StatusCode: 500,
+			})
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			By("When trying to clean up resource")
+			_, err := whenCreatingAndDeletingSchema(ctx, aSchema)
+
+			By("And reconciliation fails")
+			Expect(err).ToNot(Succeed())
+
+			By("Then failed ready condition should be set")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Cleanup)
+		})
+		It("Should update status on failed RegisterSchema", func() {
+			By("When trying to create resource with invalid schema")
+			aSchema := aSchemaWithNameStrategy(NameStrategy{
+				SubjectName: "test",
+				Format:      v1beta1.AVRO,
+				Schema:      `invalid`,
+			})
+			_, err := whenCreatingSchema(ctx, aSchema)
+
+			By("And reconciliation fails")
+			Expect(err).ToNot(Succeed())
+
+			By("Then failed ready condition should be set")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.RegisterSchema)
+		})
+		It("Should update status on failed SetCompatibilityMode", func() {
+			By("Given SetCompatibilityMode will fails in schema registry")
+			srMock.InjectError(schemaregmock.InjectedError{
+				OnApi:      schemaregmock.SetCompatibilityMode,
+				StatusCode: 500,
+			})
+
+			By("When trying to create new resource")
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			_, err := whenCreatingSchema(ctx, aSchema)
+
+			By("And reconciliation fails")
+			Expect(err).ToNot(Succeed())
+
+			By("Then failed ready condition should be set")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.SetCompatibilityMode)
+		})
+	})
+	Context("Compatibility mode", func() {
+		It("Should remove subject level compatibility mode when removed from resource", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			By("Given schema resource was registered with compatibility mode")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].CompatibilityMode).Should(Equal(v1beta1.BACKWARD))
+
+			By("When compatibility mode is removed from resource")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.Compatibility = ""
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject should fall back to global compatibility mode")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].CompatibilityMode).Should(BeEmpty())
+		})
+		It("Should not update compatibility mode which is already in place", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And SetCompatibilityMode will fail in schema registry")
+			srMock.InjectError(schemaregmock.InjectedError{
+				OnApi:      schemaregmock.SetCompatibilityMode,
+				StatusCode: 500,
+			})
+
+			By("When reconciling the resource again")
+			_, err := whenReconcilingSchema(ctx, aSchema)
+
+			By("Then reconciliation should succeed")
+			Expect(err).ShouldNot(HaveOccurred())
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+		})
+	})
+	Context("Schema contexts", func() {
+		It("Should qualify subject with schema context of resource", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.HARD)
+			aSchema.Spec.SchemaRegistry.Context = ".team-a"
+
+			By("When creating schema in schema context")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then qualified subject should be registered and reported in status")
+			qualifiedSubject := ":.team-a:" + aSchema.Spec.SubjectName
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.Subject).Should(Equal(qualifiedSubject))
+			Expect(srMock.Subjects).Should(HaveKey(qualifiedSubject))
+			Expect(srMock.Subjects[qualifiedSubject].CompatibilityMode).Should(Equal(v1beta1.BACKWARD))
+
+			By("And qualified subject should be cleaned up")
+			Ω(whenDeletingExistingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			Expect(srMock.Subjects).Should(BeEmpty())
+		})
+		It("Should fall back to schema context mapped to namespace, then to default one", func() {
+			Expect(os.Setenv("SCHEMA_REGISTRY_NAMESPACE_CONTEXTS", "other=.other, default=.team-b")).To(Succeed())
+			Expect(os.Setenv("DEFAULT_SCHEMA_REGISTRY_CONTEXT", ".fallback")).To(Succeed())
+			DeferCleanup(os.Unsetenv, "SCHEMA_REGISTRY_NAMESPACE_CONTEXTS")
+			DeferCleanup(os.Unsetenv, "DEFAULT_SCHEMA_REGISTRY_CONTEXT")
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.HARD)
+
+			By("When creating schema without schema context")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject should be qualified with schema context mapped to namespace")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.Subject).Should(Equal(":.team-b:" + aSchema.Spec.SubjectName))
+
+			By("And default schema context should be used in other namespaces")
+			aSchema.Namespace = "unmapped"
+			Expect(getSchemaContext(aSchema)).Should(Equal(".fallback"))
+		})
+	})
+	Context("Subject config", func() {
+		It("Should apply subject config defined by resource", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.SubjectConfig = &v1beta1.SubjectConfig{
+				CompatibilityGroup: "app.major.version",
+				DefaultMetadata: &v1beta1.Metadata{
+					Properties: map[string]string{"owner": "team-a"},
+				},
+			}
+
+			By("When creating schema with subject config")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject config should be applied")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(subject.CompatibilityMode).Should(Equal(v1beta1.BACKWARD))
+			Expect(subject.Config).Should(Equal(*aSchema.Spec.SubjectConfig))
+		})
+		It("Should remove subject config settings which are not defined anymore", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.SubjectConfig = &v1beta1.SubjectConfig{
+				CompatibilityGroup: "app.major.version",
+				Alias:              "other-subject",
+			}
+			By("Given schema resource was registered with subject config")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When alias is removed from resource")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.SubjectConfig.Alias = ""
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then alias should be removed, leaving remaining settings in place")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(subject.CompatibilityMode).Should(Equal(v1beta1.BACKWARD))
+			Expect(subject.Config).Should(Equal(v1beta1.SubjectConfig{CompatibilityGroup: "app.major.version"}))
+		})
+	})
+	Context("Subject mode", func() {
+		It("Should skip registration of changed schema if subject is read-only", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When subject is frozen")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.SubjectMode = v1beta1.ModeReadOnly
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			_, err := whenReconcilingSchema(ctx, aSchema)
+
+			By("Then read-only mode should be set and schema left as it is")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(srMock.Modes[aSchema.Spec.SubjectName]).Should(Equal(v1beta1.ModeReadOnly))
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+
+			By("And when schema changes")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.Schema = `"int"`
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			_, err = whenReconcilingSchema(ctx, aSchema)
+
+			By("Then registration should be skipped without failing reconciliation")
+			Expect(err).ShouldNot(HaveOccurred())
+			expectConditionWithReason(ctx, aSchema, "ReadOnly", v1beta1.RegistrationBlocked)
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.ReadOnlySubject)
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].Versions()).Should(Equal([]int{1}))
+		})
+		It("Should register schema once subject is switched back to READWRITE", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.SubjectMode = v1beta1.ModeReadOnly
+			By("Given registration was blocked by read-only mode")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.ReadOnlySubject)
+			Expect(srMock.Subjects).Should(BeEmpty())
+
+			By("When subject is switched to READWRITE mode")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.SubjectMode = v1beta1.ModeReadWrite
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject level mode should be set and schema registered")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(meta.FindStatusCondition(status.Conditions, "ReadOnly")).Should(BeNil())
+			Expect(srMock.Modes[aSchema.Spec.SubjectName]).Should(Equal(v1beta1.ModeReadWrite))
+			Expect(srMock.Subjects).Should(HaveKey(aSchema.Spec.SubjectName))
+		})
+		It("Should leave subject mode alone if resource doesn't define it", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When subject is frozen outside the operator")
+			srMock.Modes[aSchema.Spec.SubjectName] = v1beta1.ModeReadOnly
+			_, err := whenReconcilingSchema(ctx, aSchema)
+
+			By("Then subject mode should be kept")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(srMock.Modes[aSchema.Spec.SubjectName]).Should(Equal(v1beta1.ModeReadOnly))
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+		})
+		It("Should skip registration if schema registry is read-only", func() {
+			By("Given schema registry is read-only")
+			srMock.GlobalMode = v1beta1.ModeReadOnly
+
+			By("When creating schema")
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			_, err := whenCreatingSchema(ctx, aSchema)
+
+			By("Then registration should be skipped without failing reconciliation")
+			Expect(err).ShouldNot(HaveOccurred())
+			expectConditionWithReason(ctx, aSchema, "ReadOnly", v1beta1.RegistrationBlocked)
+		})
+		It("Should switch non-empty subject to IMPORT mode", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When switching subject to IMPORT mode")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.SubjectMode = v1beta1.ModeImport
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject should be in IMPORT mode")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(srMock.Modes[aSchema.Spec.SubjectName]).Should(Equal(v1beta1.ModeImport))
+		})
+	})
+	Context("Pinned schema id", func() {
+		It("Should register schema with pinned id and version", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Data.Id = 100
+			aSchema.Spec.Data.Version = 5
+
+			By("When creating schema with pinned id and version")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then schema should be registered under pinned id and version")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaId).Should(Equal(100))
+			Expect(status.SchemaVersion).Should(Equal(5))
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].Versions()).Should(Equal([]int{5}))
+
+			By("And subject mode should be restored")
+			Expect(srMock.Modes).ShouldNot(HaveKey(aSchema.Spec.SubjectName))
+		})
+		It("Should report mismatch if other schema is registered under pinned id", func() {
+			By("Given other schema is registered under id 100")
+			srMock.Register("other-subject", schemareg.RegisterSchemaReq{Schema: `"int"`, Id: 100})
+
+			By("When creating schema with pinned id 100")
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Data.Id = 100
+			_, err := whenCreatingSchema(ctx, aSchema)
+
+			By("Then IdMismatch should be reported and schema left unregistered")
+			Expect(err).Should(HaveOccurred())
+			expectConditionWithReason(ctx, aSchema, "IdMismatch", v1beta1.IdConflict)
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.PinnedId)
+			Expect(srMock.Subjects).ShouldNot(HaveKey(aSchema.Spec.SubjectName))
+		})
+		It("Should report mismatch if other schema is registered under pinned id in schema context", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.SchemaRegistry.Context = ".team-a"
+			aSchema.Spec.Data.Id = 100
+			By("Given other schema is registered under id 100 in the same schema context")
+			srMock.Register(":.team-a:other-subject", schemareg.RegisterSchemaReq{Schema: `"int"`, Id: 100})
+
+			By("When creating schema with pinned id 100 in schema context")
+			_, err := whenCreatingSchema(ctx, aSchema)
+
+			By("Then IdMismatch should be reported and schema left unregistered")
+			Expect(err).Should(HaveOccurred())
+			expectConditionWithReason(ctx, aSchema, "IdMismatch", v1beta1.IdConflict)
+			Expect(srMock.Subjects).ShouldNot(HaveKey(":.team-a:" + aSchema.Spec.SubjectName))
+		})
+		It("Should report mismatch if schema is registered under other id", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When pinning other id")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.Id = 100
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			_, err := whenReconcilingSchema(ctx, aSchema)
+
+			By("Then IdMismatch should be reported")
+			Expect(err).Should(HaveOccurred())
+			expectConditionWithReason(ctx, aSchema, "IdMismatch", v1beta1.IdConflict)
+		})
+	})
+	Context("Version history", func() {
+		It("Should replay version history against empty subject", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Versions = []v1beta1.SchemaVersion{{Schema: `"int"`}, {Schema: `"long"`}}
+
+			By("When creating schema with version history")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then history should be registered in order, followed by current schema")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaVersion).Should(Equal(3))
+			Expect(status.RegisteredVersions).Should(Equal([]int{1, 2, 3}))
+			Expect(events.Events).Should(Receive(ContainSubstring("VersionsReplayed")))
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(srMock.Schemas[subject.SchemaRefs[0].SchemaId()]).Should(Equal(`"int"`))
+			Expect(srMock.Schemas[subject.SchemaRefs[1].SchemaId()]).Should(Equal(`"long"`))
+		})
+		It("Should replay only versions missing in lagging subject", func() {
+			aSchema := aSchemaWithAdoptionMode(v1beta1.AdoptAndUpdate)
+			aSchema.Spec.Versions = []v1beta1.SchemaVersion{{Schema: `"int"`}, {Schema: `"long"`}}
+			By("Given subject holds the first historical version only")
+			srMock.RegisterSchema(aSchema.Spec.SubjectName, `"int"`)
+
+			By("When creating schema with version history")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then missing versions should be replayed")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaVersion).Should(Equal(3))
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].Versions()).Should(Equal([]int{1, 2, 3}))
+		})
+		It("Should not replay history once current schema is registered", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When adding version history")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Versions = []v1beta1.SchemaVersion{{Schema: `"int"`}}
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject should be left as it is")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].Versions()).Should(Equal([]int{1}))
+		})
+		It("Should read historical versions from ConfigMap", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("history-%d", time.Now().UnixMilli()), Namespace: "default"},
+				Data:       map[string]string{"v1.avsc": `"int"`},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).Should(Succeed())
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Versions = []v1beta1.SchemaVersion{{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+					Key:                  "v1.avsc",
+				},
+			}}
+
+			By("When creating schema with version history in ConfigMap")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then version from ConfigMap should be replayed")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(srMock.Schemas[subject.SchemaRefs[0].SchemaId()]).Should(Equal(`"int"`))
+		})
+		It("Should fail if ConfigMap of historical version is missing", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Versions = []v1beta1.SchemaVersion{{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+					Key:                  "v1.avsc",
+				},
+			}}
+
+			By("When creating schema")
+			_, err := whenCreatingSchema(ctx, aSchema)
+
+			By("Then reconciliation should fail before registering anything")
+			Expect(err).Should(HaveOccurred())
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.ReplayVersions)
+			Expect(srMock.Subjects).ShouldNot(HaveKey(aSchema.Spec.SubjectName))
+		})
+		It("Should compile historical versions authored in Avro IDL", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Data.Format = v1beta1.AVRO
+			aSchema.Spec.Data.SourceFormat = v1beta1.AVRO_IDL
+			aSchema.Spec.Data.Schema = "record Event { long id; string? name = null; }"
+			aSchema.Spec.Versions = []v1beta1.SchemaVersion{{Schema: "record Event { long id; }"}}
+
+			By("When creating schema with version history authored in Avro IDL")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then compiled historical version should be replayed")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.RegisteredVersions).Should(Equal([]int{1, 2}))
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(srMock.Schemas[subject.SchemaRefs[0].SchemaId()]).Should(Equal(
+				`{"type":"record","name":"Event","fields":[{"name":"id","type":"long"}]}`))
+		})
+		It("Should replay historical Protobuf versions with references of their imports", func() {
+			givenWellKnownTypesRegistered()
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Data.Format = v1beta1.PROTOBUF
+			aSchema.Spec.Data.Schema = `syntax = "proto3"; import "google/protobuf/timestamp.proto"; ` +
+				`message Event { google.protobuf.Timestamp at = 1; }`
+			aSchema.Spec.Versions = []v1beta1.SchemaVersion{{
+				Schema: `syntax = "proto3"; import "google/protobuf/timestamp.proto"; message Event {}`,
+			}}
+
+			By("When creating schema with Protobuf version history")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then historical version should reference imported well-known type")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(subject.Versions()).Should(Equal([]int{1, 2}))
+			Expect(srMock.SchemaReferences[subject.SchemaRefs[0].SchemaId()]).Should(Equal([]schemareg.SchemaReference{
+				{Name: "google/protobuf/timestamp.proto", Subject: "google/protobuf/timestamp.proto", Version: 1},
+			}))
+		})
+	})
+	Context("Rollback", func() {
+		givenSchemaRevertedToFirstVersion := func(policy v1beta1.RollbackPolicy) *v1beta1.KafkaSchema {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.VERSIONS)
+			aSchema.Spec.RollbackPolicy = policy
+			By("Given schema resource was registered in two versions")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.Schema = `"int"`
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].Versions()).Should(Equal([]int{1, 2}))
+
+			By("When schema is reverted to the first version")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.Schema = `"string"`
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			_, err := whenReconcilingSchema(ctx, aSchema)
+			Expect(err).ShouldNot(HaveOccurred())
+			return aSchema
+		}
+		It("Should leave newer versions without rollback policy", func() {
+			aSchema := givenSchemaRevertedToFirstVersion("")
+
+			By("Then subject should be left as it is")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaVersion).Should(Equal(1))
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].Versions()).Should(Equal([]int{1, 2}))
+		})
+		It("Should await approval of rollback", func() {
+			aSchema := givenSchemaRevertedToFirstVersion(v1beta1.RollbackDeleteNewerVersions)
+
+			By("Then rollback should await approval")
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.RollbackPending)
+			Expect(events.Events).Should(Receive(ContainSubstring("RollbackPending")))
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].Versions()).Should(Equal([]int{1, 2}))
+		})
+		It("Should soft-delete newer versions once rollback is approved", func() {
+			aSchema := givenSchemaRevertedToFirstVersion(v1beta1.RollbackDeleteNewerVersions)
+
+			By("And rollback is approved")
+			drainEvents()
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Annotations = map[string]string{"kafka.incubly.oss/approve-rollback": "1"}
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then newer version should be soft-deleted and approval consumed")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaVersion).Should(Equal(1))
+			Expect(status.RegisteredVersions).Should(Equal([]int{1}))
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(subject.Versions()).Should(Equal([]int{1}))
+			Expect(subject.SoftDeletedVersions()).Should(Equal([]int{2}))
+			Expect(events.Events).Should(Receive(ContainSubstring("RolledBack")))
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			Expect(aSchema.Annotations).ShouldNot(HaveKey("kafka.incubly.oss/approve-rollback"))
+		})
+		It("Should keep status set during reconciliation when consuming approval", func() {
+			aSchema := givenSchemaRevertedToFirstVersion(v1beta1.RollbackDeleteNewerVersions)
+
+			By("And rollback is approved")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Annotations = map[string]string{"kafka.incubly.oss/approve-rollback": "1"}
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+
+			By("And status was updated in memory only")
+			aSchema.SetCondition("Drifted", v1beta1.DriftDetected, "schema doesn't match the resource")
+
+			By("When rolling back")
+			cut := &KafkaSchemaReconciler{Client: k8sClient, Recorder: events}
+			srClient, err := schemareg.NewClient(&aSchema.Spec.SchemaRegistry, logr.Discard())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cut.rollback(ctx, aSchema.Status.Subject, aSchema, srClient, logr.Discard(), 1, []int{2})).
+				Should(Succeed())
+
+			By("Then approval should be consumed and status in memory kept")
+			stored := &v1beta1.KafkaSchema{}
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), stored)).Should(Succeed())
+			Expect(stored.Annotations).ShouldNot(HaveKey("kafka.incubly.oss/approve-rollback"))
+			Expect(aSchema.Status.Conditions).Should(ContainElement(HaveField("Type", "Drifted")))
+			Expect(aSchema.Status.RegisteredVersions).Should(Equal([]int{1}))
+		})
+	})
+	Context("Schema from ConfigMap or Secret", func() {
+		aConfigMap := func(schema string) *corev1.ConfigMap {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("schema-%d", time.Now().UnixNano()), Namespace: "default"},
+				Data:       map[string]string{"schema.avsc": schema},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).Should(Succeed())
+			return configMap
+		}
+		aSchemaFromConfigMap := func(configMap *corev1.ConfigMap) *v1beta1.KafkaSchema {
+			aSchema := aSchemaWithDriftPolicy(v1beta1.DriftReport)
+			aSchema.Spec.Data.Schema = ""
+			aSchema.Spec.Data.SchemaFrom = &v1beta1.SchemaSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+					Key:                  "schema.avsc",
+				},
+			}
+			return aSchema
+		}
+		It("Should register schema loaded from ConfigMap", func() {
+			aSchema := aSchemaFromConfigMap(aConfigMap(`"int"`))
+
+			By("When creating schema loaded from ConfigMap")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then schema from ConfigMap should be registered and its hash recorded")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(srMock.Schemas[status.SchemaId]).Should(Equal(`"int"`))
+			Expect(status.SchemaContentHash).Should(Equal(schemaContentHash(`"int"`)))
+			Expect(aSchema.Spec.Data.Schema).Should(BeEmpty())
+		})
+		It("Should register new version when ConfigMap changes", func() {
+			configMap := aConfigMap(`"int"`)
+			aSchema := aSchemaFromConfigMap(configMap)
+			By("Given schema loaded from ConfigMap was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When ConfigMap changes")
+			configMap.Data["schema.avsc"] = `"long"`
+			Expect(k8sClient.Update(ctx, configMap)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then new version should be registered, rather than reported as drift")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaVersion).Should(Equal(2))
+			Expect(srMock.Schemas[status.SchemaId]).Should(Equal(`"long"`))
+			Expect(status.SchemaContentHash).Should(Equal(schemaContentHash(`"long"`)))
+		})
+		It("Should register schema loaded from Secret", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("schema-%d", time.Now().UnixNano()), Namespace: "default"},
+				Data:       map[string][]byte{"schema.avsc": []byte(`"bytes"`)},
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Data.Schema = ""
+			aSchema.Spec.Data.SchemaFrom = &v1beta1.SchemaSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+					Key:                  "schema.avsc",
+				},
+			}
+
+			By("When creating schema loaded from Secret")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then schema from Secret should be registered")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(srMock.Schemas[status.SchemaId]).Should(Equal(`"bytes"`))
+		})
+		It("Should fail if referenced ConfigMap is missing", func() {
+			aSchema := aSchemaFromConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "missing"}})
+
+			By("When creating schema")
+			_, err := whenCreatingSchema(ctx, aSchema)
+
+			By("Then reconciliation should fail")
+			Expect(err).Should(HaveOccurred())
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.ResolveSchema)
+			Expect(srMock.Subjects).Should(BeEmpty())
+		})
+		It("Should clean up subject derived from schema after ConfigMap is deleted", func() {
+			configMap := aConfigMap(`{"type":"record","name":"Order","namespace":"org.example","fields":[]}`)
+			aSchema := aSchemaWithNameStrategy(NameStrategy{NamingStrategy: v1beta1.RECORD, Format: v1beta1.AVRO})
+			aSchema.Spec.CleanupPolicy = v1beta1.HARD
+			aSchema.Spec.Data.SchemaFrom = &v1beta1.SchemaSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+					Key:                  "schema.avsc",
+				},
+			}
+			By("Given schema loaded from ConfigMap was registered under subject named after the record")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			Expect(srMock.Subjects).Should(HaveKey("Order"))
+
+			By("And ConfigMap was deleted first (e.g. during namespace teardown)")
+			Expect(k8sClient.Delete(ctx, configMap)).Should(Succeed())
+
+			By("When deleting schema")
+			_, err := whenDeletingExistingSchema(ctx, aSchema)
+
+			By("Then subject recorded in status should be cleaned up and resource released")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(srMock.Subjects).ShouldNot(HaveKey("Order"))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, namespacedName(aSchema), aSchema))).Should(BeTrue())
+		})
+		It("Should enqueue schemas referencing changed ConfigMap", func() {
+			configMap := aConfigMap(`"int"`)
+			referencing := aSchemaFromConfigMap(configMap)
+			Expect(k8sClient.Create(ctx, referencing)).Should(Succeed())
+			other := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			other.Name = referencing.Name + "-other"
+			Expect(k8sClient.Create(ctx, other)).Should(Succeed())
+
+			By("When mapping ConfigMap to KafkaSchemas")
+			cut := &KafkaSchemaReconciler{Client: k8sClient}
+			requests := cut.schemasReferencing(referencedConfigMaps)(ctx, configMap)
+
+			By("Then only referencing schema should be enqueued")
+			Expect(requests).Should(ConsistOf(reconcile.Request{NamespacedName: namespacedName(referencing)}))
+		})
+		It("Should enqueue schemas referencing Secret known by its metadata only", func() {
+			referencing := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			referencing.Spec.Data.Schema = ""
+			referencing.Spec.Data.SchemaFrom = &v1beta1.SchemaSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "schema-secret"},
+					Key:                  "schema.avsc",
+				},
+			}
+			Expect(k8sClient.Create(ctx, referencing)).Should(Succeed())
+
+			By("When mapping Secret metadata to KafkaSchemas")
+			cut := &KafkaSchemaReconciler{Client: k8sClient}
+			secret := &metav1.PartialObjectMetadata{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+				ObjectMeta: metav1.ObjectMeta{Name: "schema-secret", Namespace: "default"},
+			}
+			requests := cut.schemasReferencing(referencedSecrets)(ctx, secret)
+
+			By("Then referencing schema should be enqueued")
+			Expect(requests).Should(ConsistOf(reconcile.Request{NamespacedName: namespacedName(referencing)}))
+		})
+	})
+	Context("Structured schema object", func() {
+		aSchemaWithObject := func(schemaObject string) *v1beta1.KafkaSchema {
+			aSchema := aSchemaWithNameStrategy(NameStrategy{
+				NamingStrategy: v1beta1.TOPIC_RECORD,
+				TopicName:      "orders",
+				Format:         v1beta1.AVRO,
+			})
+			aSchema.Spec.Data.SchemaObject = &apiextensionsv1.JSON{Raw: []byte(schemaObject)}
+			return aSchema
+		}
+		It("Should register schema object serialized deterministically", func() {
+			aSchema := aSchemaWithObject(
+				`{"type": "record", "name": "Order", "fields": [{"type": "long", "name": "id", "default": 1000, "doc": "<id>"}]}`)
+
+			By("When creating schema with schema object")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject should be named by record of schema object")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.Subject).Should(Equal("orders-Order"))
+
+			By("And schema should be registered as JSON with sorted keys")
+			expected := `{"fields":[{"default":1000,"doc":"<id>","name":"id","type":"long"}],"name":"Order","type":"record"}`
+			Expect(srMock.Schemas[status.SchemaId]).Should(Equal(expected))
+			Expect(status.SchemaContentHash).Should(Equal(schemaContentHash(expected)))
+		})
+		It("Should not register new version when only key order changes", func() {
+			aSchema := aSchemaWithObject(`{"type": "record", "name": "Order", "fields": []}`)
+			By("Given schema object was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When keys of schema object are reordered")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.SchemaObject = &apiextensionsv1.JSON{Raw: []byte(`{"fields": [], "name": "Order", "type": "record"}`)}
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then registered version should stay the same")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaVersion).Should(Equal(1))
+		})
+	})
+	Context("Avro IDL", func() {
+		anAvroIdlSchema := func(idl string) *v1beta1.KafkaSchema {
+			aSchema := aSchemaWithNameStrategy(NameStrategy{
+				NamingStrategy: v1beta1.RECORD,
+				Schema:         idl,
+				Format:         v1beta1.AVRO,
+			})
+			aSchema.Spec.Data.SourceFormat = v1beta1.AVRO_IDL
+			return aSchema
+		}
+		It("Should register schema compiled from Avro IDL with imports from ConfigMap", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("idl-%d", time.Now().UnixNano()), Namespace: "default"},
+				Data:       map[string]string{"money.avdl": "namespace org.example;\nrecord Money { long cents; }"},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).Should(Succeed())
+			aSchema := anAvroIdlSchema(`@namespace("org.example")
+protocol Orders {
+  import idl "common/money.avdl";
+  record Order { string? note = null; Money total; }
+}`)
+			aSchema.Spec.Data.ImportsFrom = []corev1.LocalObjectReference{{Name: configMap.Name}}
+
+			By("When creating schema authored in Avro IDL")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then compiled Avro schema should be registered")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.Subject).Should(Equal("Order"))
+			Expect(srMock.Schemas[status.SchemaId]).Should(Equal(
+				`{"type":"record","name":"Order","namespace":"org.example","fields":[` +
+					`{"name":"note","type":["null","string"],"default":null},` +
+					`{"name":"total","type":{"type":"record","name":"Money","fields":[{"name":"cents","type":"long"}]}}]}`))
+
+			By("And ConfigMap with imports should be watched")
+			Expect(referencedConfigMaps(aSchema)).Should(ContainElement(configMap.Name))
+		})
+		It("Should report compile errors with line numbers", func() {
+			aSchema := anAvroIdlSchema("record Order {\n  long id;\n  Customer customer;\n}")
+			aSchema.Spec.NamingStrategy = ""
+			aSchema.Spec.SubjectName = "orders-value"
+
+			By("When creating schema with invalid Avro IDL")
+			_, err := whenCreatingSchema(ctx, aSchema)
+
+			By("Then reconciliation should fail with compile error")
+			Expect(err).Should(HaveOccurred())
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.CompileSchema)
+			Expect(meta.FindStatusCondition(status.Conditions, "Ready").Message).
+				Should(Equal("Failed to compile schema: line 3, column 3: undefined type Customer"))
+			Expect(srMock.Subjects).Should(BeEmpty())
+		})
+		It("Should clean up subject of resource which no longer compiles", func() {
+			aSchema := anAvroIdlSchema("record Order { long id; }")
+			aSchema.Spec.CleanupPolicy = v1beta1.HARD
+			By("Given schema compiled from Avro IDL was registered under subject named after the record")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			Expect(srMock.Subjects).Should(HaveKey("Order"))
+
+			By("And Avro IDL was broken by an edit")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.Schema = "record Order { long id; Customer customer; }"
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+
+			By("When deleting schema")
+			_, err := whenDeletingExistingSchema(ctx, aSchema)
+
+			By("Then subject recorded in status should be cleaned up and resource released")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(srMock.Subjects).ShouldNot(HaveKey("Order"))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, namespacedName(aSchema), aSchema))).Should(BeTrue())
+		})
+	})
+	Context("Multi-file Protobuf schema", func() {
+		aProtobufSchema := func(files map[string]string) *v1beta1.KafkaSchema {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("protos-%d", time.Now().UnixNano()), Namespace: "default"},
+				Data:       files,
+			}
+			Expect(k8sClient.Create(ctx, configMap)).Should(Succeed())
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Data = v1beta1.KafkaSchemaData{
+				Format: v1beta1.PROTOBUF,
+				SchemaFrom: &v1beta1.SchemaSource{
+					ProtobufFiles: &v1beta1.ProtobufFiles{
+						ConfigMapName: configMap.Name,
+						Entrypoint:    "order.proto",
+						Paths: map[string]string{
+							"money.proto":    "common/money.proto",
+							"currency.proto": "common/currency.proto",
+						},
+						SubjectNaming: v1beta1.ImportByFileName,
+						SubjectPrefix: "proto-",
+					},
+				},
+			}
+			return aSchema
+		}
+		It("Should register imported files as references", func() {
+			givenWellKnownTypesRegistered()
+			aSchema := aProtobufSchema(map[string]string{
+				"order.proto":    `import "common/money.proto"; import "google/protobuf/timestamp.proto"; message Order {}`,
+				"money.proto":    `import "common/currency.proto"; message Money {}`,
+				"currency.proto": `message Currency {}`,
 			})
 
-			Ω(whenCreatingSchema(ctx, aSchema)).
-				ShouldNot(BeNil())
+			By("When creating schema with imports")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
 
-			By("Then resource status should be set")
+			By("Then imported files should be registered under their own subjects")
 			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
-			Expect(status.SchemaRegistryUrl).To(Equal(srMockServer.URL()))
-			Expect(status.Subject).To(Equal(aSchema.Spec.SubjectName))
-			Expect(status.SchemaId).To(BeNumerically(">", 0))
-			Expect(status.Conditions).ToNot(BeEmpty())
+			Expect(srMock.Subjects).Should(HaveKey("proto-currency.proto"))
+			Expect(srMock.Subjects).Should(HaveKey("proto-money.proto"))
+			moneyId := srMock.Subjects["proto-money.proto"].LatestSchemaId()
+			Expect(srMock.SchemaReferences[moneyId]).Should(Equal([]schemareg.SchemaReference{
+				{Name: "common/currency.proto", Subject: "proto-currency.proto", Version: 1},
+			}))
 
-			By("And status matches schema registry entries")
-			Expect(srMock.Subjects).To(HaveKey(status.Subject))
-			Expect(srMock.Schemas).To(HaveKeyWithValue(status.SchemaId, aSchema.Spec.Data.Schema))
+			By("And referenced by the schema")
+			Expect(srMock.Schemas[status.SchemaId]).Should(ContainSubstring("message Order"))
+			Expect(srMock.SchemaReferences[status.SchemaId]).Should(Equal([]schemareg.SchemaReference{
+				{Name: "common/money.proto", Subject: "proto-money.proto", Version: 1},
+				{Name: "google/protobuf/timestamp.proto", Subject: "google/protobuf/timestamp.proto", Version: 1},
+			}))
 		})
-		It("Should update status on failed NamingStrategy", func() {
-			By("When trying to create resource with invalid name strategy")
-			aSchema := aSchemaWithNameStrategy(NameStrategy{
-				Format: v1beta1.AVRO,
-				Schema: `"string"`,
+		It("Should register new version when imported file changes", func() {
+			aSchema := aProtobufSchema(map[string]string{
+				"order.proto":    `import "common/money.proto"; message Order {}`,
+				"money.proto":    `import "common/currency.proto"; message Money {}`,
+				"currency.proto": `message Currency {}`,
+			})
+			aSchema.Spec.DriftPolicy = v1beta1.DriftReport
+			By("Given schema with imports was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			registered := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+
+			By("When imported file changes")
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default",
+				Name: aSchema.Spec.Data.SchemaFrom.ProtobufFiles.ConfigMapName}, configMap)).Should(Succeed())
+			configMap.Data["money.proto"] = `import "common/currency.proto"; message Money { int64 amount = 1; }`
+			Expect(k8sClient.Update(ctx, configMap)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then new version referencing changed import should be registered, rather than reported as drift")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaVersion).Should(Equal(2))
+			Expect(status.SchemaContentHash).ShouldNot(Equal(registered.SchemaContentHash))
+			Expect(srMock.SchemaReferences[status.SchemaId]).Should(Equal([]schemareg.SchemaReference{
+				{Name: "common/money.proto", Subject: "proto-money.proto", Version: 2},
+			}))
+			Expect(status.Conditions).ShouldNot(ContainElement(
+				HaveField("Reason", v1beta1.DriftDetected.Name)))
+		})
+		It("Should reject import cycles", func() {
+			aSchema := aProtobufSchema(map[string]string{
+				"order.proto":    `import "common/money.proto"; message Order {}`,
+				"money.proto":    `import "common/currency.proto"; message Money {}`,
+				"currency.proto": `import "common/money.proto"; message Currency {}`,
 			})
+
+			By("When creating schema with import cycle")
 			_, err := whenCreatingSchema(ctx, aSchema)
 
-			By("And reconciliation fails")
-			Expect(err).ToNot(Succeed())
+			By("Then reconciliation should fail before registering anything")
+			Expect(err).Should(MatchError(ContainSubstring("import cycle")))
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.RegisterImports)
+			Expect(srMock.Subjects).Should(BeEmpty())
+		})
+	})
+	Context("Protobuf well-known types", func() {
+		aSchemaImporting := func(imports ...string) *v1beta1.KafkaSchema {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Data.Format = v1beta1.PROTOBUF
+			aSchema.Spec.Data.Schema = `syntax = "proto3";`
+			for _, imported := range imports {
+				aSchema.Spec.Data.Schema += fmt.Sprintf(` import "%s";`, imported)
+			}
+			aSchema.Spec.Data.Schema += ` message Event {}`
+			return aSchema
+		}
+		It("Should register imported well-known types and reference them", func() {
+			givenWellKnownTypesRegistered()
+			aSchema := aSchemaImporting("google/protobuf/timestamp.proto", "google/protobuf/type.proto")
 
-			By("Then failed ready condition should be set")
-			expectReadyConditionWithReason(ctx, aSchema, v1beta1.NameStrategy)
+			By("When creating schema importing well-known types")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then well-known types should be registered, including transitive imports")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(srMock.Subjects).Should(HaveKey("google/protobuf/any.proto"))
+			Expect(srMock.Subjects).Should(HaveKey("google/protobuf/source_context.proto"))
+			typeId := srMock.Subjects["google/protobuf/type.proto"].LatestSchemaId()
+			Expect(srMock.Schemas[typeId]).Should(ContainSubstring("message Type"))
+			Expect(srMock.SchemaReferences[typeId]).Should(HaveLen(2))
 
+			By("And referenced by the schema")
+			Expect(srMock.SchemaReferences[status.SchemaId]).Should(Equal([]schemareg.SchemaReference{
+				{Name: "google/protobuf/timestamp.proto", Subject: "google/protobuf/timestamp.proto", Version: 1},
+				{Name: "google/protobuf/type.proto", Subject: "google/protobuf/type.proto", Version: 1},
+			}))
 		})
-		It("Should update status on failed Cleanup", func() {
-			By("Given DeleteSubject will fail in schema registry")
-			srMock.InjectError(schemaregmock.InjectedError{
-				OnApi:      schemaregmock.DeleteSubject,
-				StatusCode: 500,
-			})
+		It("Should leave well-known types to schema registry by default", func() {
+			aSchema := aSchemaImporting("google/protobuf/timestamp.proto")
+
+			By("When creating schema importing well-known types")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then schema should be registered without references")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(srMock.Subjects).Should(HaveLen(1))
+			Expect(srMock.SchemaReferences[status.SchemaId]).Should(BeNil())
+		})
+	})
+	Context("Schema metadata", func() {
+		It("Should register schema along with its metadata", func() {
 			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
-			By("When trying to clean up resource")
-			_, err := whenCreatingAndDeletingSchema(ctx, aSchema)
+			aSchema.Spec.Data.Metadata = &v1beta1.Metadata{
+				Properties: map[string]string{"owner": "team-a"},
+				Tags:       map[string][]string{"**.ssn": {"PII"}},
+				Sensitive:  []string{"owner"},
+			}
 
-			By("And reconciliation fails")
-			Expect(err).ToNot(Succeed())
+			By("When creating schema with metadata")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
 
-			By("Then failed ready condition should be set")
-			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Cleanup)
+			By("Then metadata should be registered with the schema")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(srMock.Metadata[status.SchemaId]).Should(Equal(aSchema.Spec.Data.Metadata))
 		})
-		It("Should update status on failed RegisterSchema", func() {
-			By("When trying to create resource with invalid schema")
-			aSchema := aSchemaWithNameStrategy(NameStrategy{
-				SubjectName: "test",
-				Format:      v1beta1.AVRO,
-				Schema:      `invalid`,
-			})
+		It("Should register new version when metadata changes", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Data.Metadata = &v1beta1.Metadata{
+				Properties: map[string]string{"owner": "team-a"},
+			}
+			By("Given schema resource was registered with metadata")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When metadata changes")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.Metadata.Properties["owner"] = "team-b"
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then new version should be registered")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaVersion).Should(Equal(2))
+			Expect(srMock.Metadata[status.SchemaId].Properties).Should(HaveKeyWithValue("owner", "team-b"))
+		})
+		It("Should propagate labels and annotations of resource to metadata properties", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Labels = map[string]string{"owner": "team-a", "ignored": "true"}
+			aSchema.Annotations = map[string]string{"pii": "true"}
+			aSchema.Spec.Data.Metadata = &v1beta1.Metadata{
+				Properties: map[string]string{"owner": "team-b"},
+			}
+			aSchema.Spec.Data.MetadataFromResource = &v1beta1.MetadataFromResource{
+				Labels:      []string{"owner"},
+				Annotations: []string{"pii"},
+			}
+
+			By("When creating schema with metadata propagated from resource")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then properties of resource should be registered, explicit ones taking precedence")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(srMock.Metadata[status.SchemaId].Properties).Should(Equal(map[string]string{
+				"k8s.namespace": aSchema.Namespace,
+				"k8s.name":      aSchema.Name,
+				"owner":         "team-b",
+				"pii":           "true",
+			}))
+		})
+	})
+	Context("Schema rule set", func() {
+		aRuleSet := func(expr string) *v1beta1.RuleSet {
+			return &v1beta1.RuleSet{
+				DomainRules: []v1beta1.Rule{{
+					Name: "checkLength",
+					Kind: v1beta1.RuleCondition,
+					Mode: v1beta1.RuleWrite,
+					Type: "CEL",
+					Expr: expr,
+				}},
+			}
+		}
+		It("Should register new version when rule set changes", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Data.RuleSet = aRuleSet("size(message) > 0")
+			By("Given schema resource was registered with rule set")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(srMock.RuleSets[status.SchemaId]).Should(Equal(aSchema.Spec.Data.RuleSet))
+
+			By("When rule set changes")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.RuleSet = aRuleSet("size(message) > 1")
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then new version should be registered")
+			status = expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaVersion).Should(Equal(2))
+			Expect(srMock.RuleSets[status.SchemaId]).Should(Equal(aSchema.Spec.Data.RuleSet))
+		})
+		It("Should not register schema with invalid CEL expression", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			aSchema.Spec.Data.RuleSet = aRuleSet("size(message) >")
+
+			By("When creating schema with invalid rule set")
 			_, err := whenCreatingSchema(ctx, aSchema)
 
-			By("And reconciliation fails")
-			Expect(err).ToNot(Succeed())
+			By("Then reconciliation should fail before registration")
+			Expect(err).Should(HaveOccurred())
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.ValidateRuleSet)
+			Expect(srMock.Subjects).Should(BeEmpty())
+		})
+	})
+	Context("Drift detection", func() {
+		It("Should report drift without modifying registry if drift policy is Report", func() {
+			aSchema := aSchemaWithDriftPolicy(v1beta1.DriftReport)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And subject compatibility mode was changed directly in registry")
+			srMock.Subjects[aSchema.Spec.SubjectName].CompatibilityMode = v1beta1.FULL
 
-			By("Then failed ready condition should be set")
-			expectReadyConditionWithReason(ctx, aSchema, v1beta1.RegisterSchema)
+			By("When reconciling the resource again")
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then drift should be reported")
+			status := expectConditionWithReason(ctx, aSchema, "Drifted", v1beta1.DriftDetected)
+			Expect(meta.FindStatusCondition(status.Conditions, "Drifted").Message).
+				Should(ContainSubstring("compatibility"))
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+
+			By("And registry should be left untouched")
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].CompatibilityMode).Should(Equal(v1beta1.FULL))
 		})
-		It("Should update status on failed SetCompatibilityMode", func() {
-			By("Given SetCompatibilityMode will fails in schema registry")
-			srMock.InjectError(schemaregmock.InjectedError{
-				OnApi:      schemaregmock.SetCompatibilityMode,
-				StatusCode: 500,
+		It("Should correct drift if drift policy is Correct", func() {
+			aSchema := aSchemaWithDriftPolicy(v1beta1.DriftCorrect)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And subject compatibility mode was changed directly in registry")
+			srMock.Subjects[aSchema.Spec.SubjectName].CompatibilityMode = v1beta1.FULL
+
+			By("When reconciling the resource again")
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then drift should be corrected")
+			expectConditionWithReason(ctx, aSchema, "Drifted", v1beta1.DriftCorrected)
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].CompatibilityMode).Should(Equal(v1beta1.BACKWARD))
+		})
+		It("Should report drift if newer version was registered directly in registry", func() {
+			aSchema := aSchemaWithDriftPolicy(v1beta1.DriftCorrect)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And newer schema version was registered directly in registry")
+			srMock.RegisterSchema(aSchema.Spec.SubjectName, `"int"`)
+
+			By("When reconciling the resource again")
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then drift should be reported, since re-registering doesn't change the latest version")
+			status := expectConditionWithReason(ctx, aSchema, "Drifted", v1beta1.DriftDetected)
+			Expect(meta.FindStatusCondition(status.Conditions, "Drifted").Message).
+				Should(ContainSubstring("latest version is 2, expected 1"))
+		})
+		It("Should not report drift after older schema was re-applied", func() {
+			aSchema := aSchemaWithDriftPolicy(v1beta1.DriftCorrect)
+			By("Given two versions were registered by the resource")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.Schema = `"int"`
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("When the first schema is re-applied (without rollback)")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Data.Schema = `"string"`
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.SchemaVersion).Should(Equal(1))
+			Expect(status.LatestVersion).Should(Equal(2))
+
+			By("Then reconciling it again shouldn't report drift")
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			expectConditionWithReason(ctx, aSchema, "Drifted", v1beta1.InSync)
+
+			By("And newer version registered directly in registry should still be reported")
+			srMock.RegisterSchema(aSchema.Spec.SubjectName, `"bytes"`)
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			status = expectConditionWithReason(ctx, aSchema, "Drifted", v1beta1.DriftDetected)
+			Expect(meta.FindStatusCondition(status.Conditions, "Drifted").Message).
+				Should(ContainSubstring("latest version is 3, expected 2"))
+		})
+		It("Should report soft-deleted subject", func() {
+			aSchema := aSchemaWithDriftPolicy(v1beta1.DriftReport)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And subject was soft-deleted directly in registry")
+			Ω(srMock.DeleteSubject(aSchema.Spec.SubjectName, false)).ShouldNot(BeNil())
+
+			By("When reconciling the resource again")
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then drift should be reported")
+			status := expectConditionWithReason(ctx, aSchema, "Drifted", v1beta1.DriftDetected)
+			Expect(meta.FindStatusCondition(status.Conditions, "Drifted").Message).
+				Should(ContainSubstring("soft-deleted"))
+			Expect(srMock.Subjects).Should(BeEmpty())
+		})
+		It("Should not check drift if drift policy is Ignore", func() {
+			aSchema := aSchemaWithDriftPolicy(v1beta1.DriftIgnore)
+			By("Given schema resource was registered")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+			By("And subject compatibility mode was changed directly in registry")
+			srMock.Subjects[aSchema.Spec.SubjectName].CompatibilityMode = v1beta1.FULL
+
+			By("When reconciling the resource again")
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then drift condition shouldn't be set")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(meta.FindStatusCondition(status.Conditions, "Drifted")).Should(BeNil())
+		})
+	})
+	Context("Restoring soft-deleted subjects", func() {
+		givenSoftDeletedSubject := func(subjectName string) (int, int) {
+			By("Given subject with two versions was soft-deleted")
+			firstId := srMock.RegisterSchema(subjectName, `"int"`)
+			secondId := srMock.RegisterSchema(subjectName, `"long"`)
+			Ω(srMock.DeleteSubject(subjectName, false)).ShouldNot(BeNil())
+			return firstId, secondId
+		}
+
+		It("Should re-register previous versions before registering schema", func() {
+			aSchema := aSchemaWithAdoptionMode(v1beta1.AdoptionFail)
+			aSchema.Spec.RestoreSoftDeleted = true
+			firstId, secondId := givenSoftDeletedSubject(aSchema.Spec.SubjectName)
+
+			By("When creating schema restoring soft-deleted subject")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then previous versions should be restored in order, followed by the schema")
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(subject).ShouldNot(BeNil())
+			Expect(subject.Versions()).Should(Equal([]int{3, 4, 5}))
+			Expect(subject.SchemaRefs[0].SchemaId()).Should(Equal(firstId))
+			Expect(subject.SchemaRefs[1].SchemaId()).Should(Equal(secondId))
+
+			By("And restored subject should be adopted")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.Adoption).ShouldNot(BeNil())
+			Expect(status.Adoption.Version).Should(Equal(4))
+			Expect(status.SchemaVersion).Should(Equal(5))
+			Expect(events.Events).Should(Receive(ContainSubstring("SubjectRestored")))
+		})
+		It("Should restore references of soft-deleted versions", func() {
+			aSchema := aSchemaWithAdoptionMode(v1beta1.AdoptionFail)
+			aSchema.Spec.RestoreSoftDeleted = true
+			By("Given soft-deleted subject had version referencing another subject")
+			srMock.RegisterSchema("restore-common", `"string"`)
+			references := []schemareg.SchemaReference{{Name: "common", Subject: "restore-common", Version: 1}}
+			referencingId := srMock.Register(aSchema.Spec.SubjectName, schemareg.RegisterSchemaReq{
+				Schema:     `"int"`,
+				References: references,
 			})
+			Ω(srMock.DeleteSubject(aSchema.Spec.SubjectName, false)).ShouldNot(BeNil())
 
-			By("When trying to create new resource")
-			aSchema := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			By("When creating schema restoring soft-deleted subject")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then restored version should keep its schema id and references")
+			subject := srMock.Subjects[aSchema.Spec.SubjectName]
+			Expect(subject).ShouldNot(BeNil())
+			Expect(subject.SchemaRefs[0].SchemaId()).Should(Equal(referencingId))
+			Expect(srMock.SchemaReferences[referencingId]).Should(Equal(references))
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+		})
+		It("Should not restore soft-deleted subject unless requested", func() {
+			aSchema := aSchemaWithAdoptionMode(v1beta1.AdoptionFail)
+			givenSoftDeletedSubject(aSchema.Spec.SubjectName)
+
+			By("When creating schema")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then only the schema should be registered")
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].Versions()).Should(Equal([]int{3}))
+		})
+	})
+	Context("Delayed and TTL cleanup", func() {
+		It("Should postpone cleanup until cleanup delay elapses", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.HARD)
+			aSchema.Spec.CleanupDelay = &metav1.Duration{Duration: time.Hour}
+
+			By("When deleting schema with cleanup delay")
+			result, err := whenCreatingAndDeletingSchema(ctx, aSchema)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			By("Then cleanup should be scheduled")
+			Expect(result.RequeueAfter).Should(BeNumerically("~", time.Hour, time.Minute))
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.CleanupScheduled)
+			Expect(status.ScheduledCleanupTime).ShouldNot(BeNil())
+			Expect(status.ScheduledCleanupTime.Time).Should(
+				BeTemporally("~", aSchema.DeletionTimestamp.Add(time.Hour), time.Second))
+
+			By("And subject should be kept in registry")
+			Expect(srMock.Subjects).Should(HaveKey(aSchema.Spec.SubjectName))
+		})
+		It("Should perform cleanup once cleanup delay elapsed", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.HARD)
+			aSchema.Spec.CleanupDelay = &metav1.Duration{Duration: time.Millisecond}
+
+			By("When deleting schema with elapsed cleanup delay")
+			Ω(whenCreatingAndDeletingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then subject should be deleted and resource released")
+			Expect(srMock.Subjects).Should(BeEmpty())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, namespacedName(aSchema), aSchema))).Should(BeTrue())
+		})
+		It("Should requeue resource no later than its TTL expiry", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.HARD)
+			aSchema.Spec.Ttl = &metav1.Duration{Duration: time.Minute}
+
+			By("When creating schema with TTL")
+			result, err := whenCreatingSchema(ctx, aSchema)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			By("Then it should be requeued before expiry")
+			Expect(result.RequeueAfter).Should(BeNumerically("<=", time.Minute))
+			Expect(result.RequeueAfter).Should(BeNumerically(">", 0))
+		})
+		It("Should delete resource which outlived its TTL", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.HARD)
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Given schema outlived its TTL")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Ttl = &metav1.Duration{Duration: time.Millisecond}
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+
+			By("When reconciling schema")
+			Ω(whenReconcilingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then resource should be marked for deletion")
+			Expect(aSchema.DeletionTimestamp.IsZero()).Should(BeFalse())
+			Expect(events.Events).Should(Receive(ContainSubstring("TtlExpired")))
+
+			By("And subject should be cleaned up on next reconciliation")
+			Ω(whenReconcilingDeletedSchema(ctx, aSchema)).ShouldNot(BeNil())
+			Expect(srMock.Subjects).Should(BeEmpty())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, namespacedName(aSchema), aSchema))).Should(BeTrue())
+		})
+		It("Should keep protected resource which outlived its TTL until it's unlocked", func() {
+			aSchema := aSchemaWithCleanupPolicy(v1beta1.HARD)
+			protected := true
+			aSchema.Spec.DeletionProtection = &protected
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Given protected schema outlived its TTL")
+			drainEvents()
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Spec.Ttl = &metav1.Duration{Duration: time.Millisecond}
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+
+			By("When reconciling schema with deletion protection enforced")
+			cut := &KafkaSchemaReconciler{
+				Client:             k8sClient,
+				Scheme:             k8sClient.Scheme(),
+				Recorder:           events,
+				DeletionProtection: &v1beta1.KafkaSchemaValidator{Reader: k8sClient},
+			}
+			Ω(cut.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName(aSchema)})).ShouldNot(BeNil())
+
+			By("Then resource should be kept and expiry reported")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			Expect(aSchema.DeletionTimestamp.IsZero()).Should(BeTrue())
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			expectConditionWithReason(ctx, aSchema, "TtlExpired", v1beta1.DeletionProtected)
+			Expect(events.Events).Should(Receive(ContainSubstring("TtlExpired")))
+
+			By("And expiry should be reported only once")
+			Ω(cut.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName(aSchema)})).ShouldNot(BeNil())
+			Expect(events.Events).ShouldNot(Receive(ContainSubstring("TtlExpired")))
+
+			By("And resource should be deleted once unlocked")
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			aSchema.Annotations = map[string]string{v1beta1.UnlockDeletionAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, aSchema)).Should(Succeed())
+			Ω(cut.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName(aSchema)})).ShouldNot(BeNil())
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			Expect(aSchema.DeletionTimestamp.IsZero()).Should(BeFalse())
+		})
+	})
+	Context("Adoption", func() {
+		It("Should fail if subject exists and adoption mode is Fail", func() {
+			aSchema := aSchemaWithAdoptionMode(v1beta1.AdoptionFail)
+			By("Given subject was registered directly in registry")
+			srMock.RegisterSchema(aSchema.Spec.SubjectName, `"int"`)
+
+			By("When creating new resource")
 			_, err := whenCreatingSchema(ctx, aSchema)
 
-			By("And reconciliation fails")
-			Expect(err).ToNot(Succeed())
+			By("Then reconciliation should fail")
+			Expect(err).Should(HaveOccurred())
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Adopt)
 
-			By("Then failed ready condition should be set")
-			expectReadyConditionWithReason(ctx, aSchema, v1beta1.SetCompatibilityMode)
+			By("And subject should be left untouched")
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].SchemaRefs).Should(HaveLen(1))
+		})
+		It("Should adopt subject if it matches the resource and adoption mode is AdoptIfMatches", func() {
+			aSchema := aSchemaWithAdoptionMode(v1beta1.AdoptIfMatches)
+			By("Given matching subject was registered directly in registry")
+			schemaId := srMock.RegisterSchema(aSchema.Spec.SubjectName, aSchema.Spec.Data.Schema)
+			srMock.Subjects[aSchema.Spec.SubjectName].CompatibilityMode = aSchema.Spec.Data.Compatibility
+
+			By("When creating new resource")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then adoption should be recorded")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.Adoption).ShouldNot(BeNil())
+			Expect(status.Adoption.Version).Should(Equal(1))
+			Expect(status.Adoption.SchemaId).Should(Equal(schemaId))
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), aSchema)).Should(Succeed())
+			Expect(aSchema.Annotations).Should(HaveKeyWithValue(adoptedVersionAnnotation, "1"))
+
+			By("And no new version should be registered")
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].SchemaRefs).Should(HaveLen(1))
+		})
+		It("Should keep status set during reconciliation when annotating adopted resource", func() {
+			aSchema := aSchemaWithAdoptionMode(v1beta1.AdoptIfMatches)
+			Expect(k8sClient.Create(ctx, aSchema)).Should(Succeed())
+
+			By("Given status was updated in memory only")
+			aSchema.Status.Adoption = &v1beta1.Adoption{Version: 3}
+			aSchema.SetCondition("Drifted", v1beta1.DriftDetected, "schema doesn't match the resource")
+
+			By("When annotating resource")
+			cut := &KafkaSchemaReconciler{Client: k8sClient}
+			Expect(cut.patchAnnotations(ctx, aSchema, func(annotations map[string]string) {
+				annotations[adoptedVersionAnnotation] = "3"
+			})).Should(Succeed())
+
+			By("Then annotation should be stored")
+			stored := &v1beta1.KafkaSchema{}
+			Expect(k8sClient.Get(ctx, namespacedName(aSchema), stored)).Should(Succeed())
+			Expect(stored.Annotations).Should(HaveKeyWithValue(adoptedVersionAnnotation, "3"))
+
+			By("And status in memory should be kept")
+			Expect(aSchema.Status.Adoption).Should(Equal(&v1beta1.Adoption{Version: 3}))
+			Expect(aSchema.Status.Conditions).Should(ContainElement(HaveField("Type", "Drifted")))
+			Expect(aSchema.ResourceVersion).Should(Equal(stored.ResourceVersion))
+		})
+		It("Should fail if subject doesn't match the resource and adoption mode is AdoptIfMatches", func() {
+			aSchema := aSchemaWithAdoptionMode(v1beta1.AdoptIfMatches)
+			By("Given different subject was registered directly in registry")
+			srMock.RegisterSchema(aSchema.Spec.SubjectName, `"int"`)
+
+			By("When creating new resource")
+			_, err := whenCreatingSchema(ctx, aSchema)
+
+			By("Then reconciliation should fail")
+			Expect(err).Should(HaveOccurred())
+			expectReadyConditionWithReason(ctx, aSchema, v1beta1.Adopt)
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].SchemaRefs).Should(HaveLen(1))
+		})
+		It("Should adopt and update subject if adoption mode is AdoptAndUpdate", func() {
+			aSchema := aSchemaWithAdoptionMode(v1beta1.AdoptAndUpdate)
+			By("Given different subject was registered directly in registry")
+			srMock.RegisterSchema(aSchema.Spec.SubjectName, `"int"`)
+
+			By("When creating new resource")
+			Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
+
+			By("Then adoption should be recorded")
+			status := expectReadyConditionWithReason(ctx, aSchema, v1beta1.Complete)
+			Expect(status.Adoption).ShouldNot(BeNil())
+			Expect(status.Adoption.Version).Should(Equal(1))
+
+			By("And schema should be registered as new version")
+			Expect(srMock.Subjects[aSchema.Spec.SubjectName].SchemaRefs).Should(HaveLen(2))
+			Expect(status.SchemaVersion).Should(Equal(2))
 		})
 	})
 })
 
+func expectConditionWithReason(
+	ctx context.Context,
+	schema *v1beta1.KafkaSchema,
+	conditionType string,
+	reason v1beta1.ReadyReason) v1beta1.KafkaSchemaStatus {
+	current := &v1beta1.KafkaSchema{}
+
+	ExpectWithOffset(1, k8sClient.SubResource("status").Get(ctx, schema, current)).
+		To(Succeed())
+	status := current.Status
+
+	condition := meta.FindStatusCondition(status.Conditions, conditionType)
+	ExpectWithOffset(1, condition).ToNot(BeNil())
+	ExpectWithOffset(1, condition.Status).Should(BeEquivalentTo(reason.Status))
+	ExpectWithOffset(1, condition.Reason).To(BeEquivalentTo(reason.Name))
+	return status
+}
+
 func expectReadyConditionWithReason(ctx context.Context, schema *v1beta1.KafkaSchema, reason v1beta1.ReadyReason) v1beta1.KafkaSchemaStatus {
 	current := &v1beta1.KafkaSchema{}
 
@@ -318,6 +1880,16 @@ func expectReadyConditionWithReason(ctx context.Context, schema *v1beta1.KafkaSc
 	return status
 }
 
+// events records events emitted by reconcilers under test
+var events = record.NewFakeRecorder(100)
+
+// drainEvents discards events left by previous specs
+func drainEvents() {
+	for len(events.Events) > 0 {
+		<-events.Events
+	}
+}
+
 func whenCreatingAndDeletingSchema(ctx context.Context, aSchema *v1beta1.KafkaSchema) (ctrl.Result, error) {
 
 	Ω(whenCreatingSchema(ctx, aSchema)).ShouldNot(BeNil())
@@ -333,8 +1905,9 @@ func whenDeletingExistingSchema(ctx context.Context, aSchema *v1beta1.KafkaSchem
 	lookupName := namespacedName(aSchema)
 
 	cut := &KafkaSchemaReconciler{
-		Client: k8sClient,
-		Scheme: k8sClient.Scheme(),
+		Client:   k8sClient,
+		Scheme:   k8sClient.Scheme(),
+		Recorder: events,
 	}
 
 	By("-- deleting schema")
@@ -348,11 +1921,23 @@ func whenDeletingExistingSchema(ctx context.Context, aSchema *v1beta1.KafkaSchem
 	return cut.Reconcile(ctx, reconcile.Request{NamespacedName: lookupName})
 }
 
+func whenReconcilingDeletedSchema(ctx context.Context, aSchema *v1beta1.KafkaSchema) (ctrl.Result, error) {
+	cut := &KafkaSchemaReconciler{
+		Client:   k8sClient,
+		Scheme:   k8sClient.Scheme(),
+		Recorder: events,
+	}
+
+	By("-- reconciling deleted schema")
+	return cut.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName(aSchema)})
+}
+
 func whenCreatingSchema(ctx context.Context, aSchema *v1beta1.KafkaSchema) (ctrl.Result, error) {
 	lookupName := namespacedName(aSchema)
 	cut := &KafkaSchemaReconciler{
-		Client: k8sClient,
-		Scheme: k8sClient.Scheme(),
+		Client:   k8sClient,
+		Scheme:   k8sClient.Scheme(),
+		Recorder: events,
 	}
 
 	By("-- creating schema")
@@ -363,6 +1948,21 @@ func whenCreatingSchema(ctx context.Context, aSchema *v1beta1.KafkaSchema) (ctrl
 	return cut.Reconcile(ctx, reconcile.Request{NamespacedName: lookupName})
 }
 
+func whenReconcilingSchema(ctx context.Context, aSchema *v1beta1.KafkaSchema) (ctrl.Result, error) {
+	lookupName := namespacedName(aSchema)
+	cut := &KafkaSchemaReconciler{
+		Client:   k8sClient,
+		Scheme:   k8sClient.Scheme(),
+		Recorder: events,
+	}
+
+	By("-- reconciling schema")
+	result, err := cut.Reconcile(ctx, reconcile.Request{NamespacedName: lookupName})
+	ExpectWithOffset(1, k8sClient.Get(ctx, lookupName, aSchema)).
+		To(Succeed())
+	return result, err
+}
+
 func namespacedName(resource *v1beta1.KafkaSchema) types.NamespacedName {
 	return types.NamespacedName{Namespace: resource.Namespace, Name: resource.Name}
 }
@@ -385,6 +1985,18 @@ func aSchemaWithCleanupPolicy(policy v1beta1.CleanupPolicy) *v1beta1.KafkaSchema
 	}
 }
 
+func aSchemaWithDriftPolicy(policy v1beta1.DriftPolicy) *v1beta1.KafkaSchema {
+	aSchema := aSchemaWithCleanupPolicy(v1beta1.DISABLED)
+	aSchema.Spec.DriftPolicy = policy
+	return aSchema
+}
+
+func aSchemaWithAdoptionMode(mode v1beta1.AdoptionMode) *v1beta1.KafkaSchema {
+	aSchema := aSchemaWithCleanupPolicy(v1beta1.DISABLED)
+	aSchema.Spec.Adoption = mode
+	return aSchema
+}
+
 type NameStrategy struct {
 	NamingStrategy v1beta1.NamingStrategy
 	SubjectName    string
@@ -412,3 +2024,9 @@ func aSchemaWithNameStrategy(args NameStrategy) *v1beta1.KafkaSchema {
 		},
 	}
 }
+
+// givenWellKnownTypesRegistered enables (opt-in) registration of Protobuf well-known types for the spec
+func givenWellKnownTypesRegistered() {
+	ExpectWithOffset(1, os.Setenv("REGISTER_WELL_KNOWN_TYPES", "true")).To(Succeed())
+	DeferCleanup(os.Unsetenv, "REGISTER_WELL_KNOWN_TYPES")
+}
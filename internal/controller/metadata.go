@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"maps"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+)
+
+/*
+schemaMetadata returns metadata registered along with the schema: properties propagated from the resource
+(see MetadataFromResource) merged with metadata explicitly defined by the resource.
+Returns nil if the resource defines no metadata
+*/
+func schemaMetadata(res *v1beta1.KafkaSchema) *v1beta1.Metadata {
+	explicit := res.Spec.Data.Metadata
+	fromResource := res.Spec.Data.MetadataFromResource
+	if fromResource == nil {
+		return explicit
+	}
+
+	metadata := &v1beta1.Metadata{}
+	if explicit != nil {
+		metadata = explicit.DeepCopy()
+	}
+	properties := map[string]string{
+		"k8s.namespace": res.Namespace,
+		"k8s.name":      res.Name,
+	}
+	for _, label := range fromResource.Labels {
+		if value, ok := res.Labels[label]; ok {
+			properties[label] = value
+		}
+	}
+	for _, annotation := range fromResource.Annotations {
+		if value, ok := res.Annotations[annotation]; ok {
+			properties[annotation] = value
+		}
+	}
+	maps.Copy(properties, metadata.Properties)
+	metadata.Properties = properties
+	return metadata
+}
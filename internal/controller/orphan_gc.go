@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+
+	"github.com/go-logr/logr"
+)
+
+/*
+OrphanGcPolicy defines how SchemaRegistryInventoryReporter treats orphaned subjects
+created by the operator (i.e. subjects whose KafkaSchema resources were deleted
+without cleanup - force-deleted or deleted while the operator was down).
+Subjects adopted or imported by the operator are never garbage-collected.
+*/
+type OrphanGcPolicy string
+
+const (
+	GcDisabled OrphanGcPolicy = "DISABLED"
+	GcReport   OrphanGcPolicy = "REPORT"
+	GcSoft     OrphanGcPolicy = "SOFT"
+	GcHard     OrphanGcPolicy = "HARD"
+)
+
+/*
+collectOrphans marks orphaned subjects eligible for garbage collection as Collectable
+and (depending on GcPolicy) deletes them from schema registry.
+Failures are logged and don't stop the inventory from being reported -
+collection is retried on next report.
+*/
+func (r *SchemaRegistryInventoryReporter) collectOrphans(
+	status *v1beta1.SchemaRegistryInventoryStatus,
+	srClient *schemareg.SrClient,
+	logger logr.Logger) {
+
+	policy := r.GcPolicy
+	if len(policy) == 0 || policy == GcDisabled {
+		return
+	}
+
+	var remaining []v1beta1.InventorySubject
+	for _, orphaned := range status.OrphanedSubjects {
+		orphaned.Collectable = orphaned.Created &&
+			orphaned.OrphanedSince != nil &&
+			time.Since(orphaned.OrphanedSince.Time) >= r.GcGracePeriod
+		if !orphaned.Collectable || policy == GcReport {
+			remaining = append(remaining, orphaned)
+			continue
+		}
+		if err := collectOrphan(orphaned.Subject, policy, srClient); err != nil {
+			logger.Error(err, fmt.Sprintf("Failed to garbage-collect orphaned subject %s (owner: %s)",
+				orphaned.Subject, orphaned.Owner))
+			remaining = append(remaining, orphaned)
+			continue
+		}
+		logger.Info(fmt.Sprintf("Garbage-collected orphaned subject %s (owner: %s, policy: %s)",
+			orphaned.Subject, orphaned.Owner, policy))
+		if policy == GcSoft {
+			status.SoftDeletedSubjects = append(status.SoftDeletedSubjects, orphaned.Subject)
+		}
+	}
+	status.OrphanedSubjects = remaining
+	sort.Strings(status.SoftDeletedSubjects)
+}
+
+func collectOrphan(subject string, policy OrphanGcPolicy, srClient *schemareg.SrClient) error {
+	switch policy {
+	case GcSoft:
+		return srClient.DeleteSubject(subject, false)
+	case GcHard:
+		if err := srClient.DeleteSubject(subject, false); err != nil {
+			return err
+		}
+		return srClient.DeleteSubject(subject, true)
+	default:
+		return fmt.Errorf("unsupported orphan garbage collection policy %q", policy)
+	}
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+)
+
+// pinnedIdMismatch is reported if schema registry doesn't match schema id or version pinned by the resource
+type pinnedIdMismatch struct {
+	msg string
+}
+
+func (e pinnedIdMismatch) Error() string {
+	return e.msg
+}
+
+func isPinnedIdMismatch(err error) bool {
+	return errors.As(err, &pinnedIdMismatch{})
+}
+
+func isPinned(res *v1beta1.KafkaSchema) bool {
+	return res.Spec.Data.Id > 0 || res.Spec.Data.Version > 0
+}
+
+/*
+registerPinned registers the schema with pinned id and/or version, temporarily switching the subject
+to IMPORT mode (unless it's in IMPORT mode already). Schema registered already is only verified to match the pins.
+Registration is refused if schema registry holds different schema under pinned id
+*/
+func registerPinned(
+	subjectName string,
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient,
+	registerReq schemareg.RegisterSchemaReq,
+	previouslyRegistered *schemareg.SubjectSchema) (int, error) {
+
+	data := res.Spec.Data
+	if previouslyRegistered != nil {
+		return previouslyRegistered.Id, verifyPins(data, previouslyRegistered)
+	}
+	if data.Id > 0 {
+		existing, err := srClient.GetSchemaById(data.Id, subjectName)
+		if err != nil {
+			return 0, err
+		}
+		if existing != nil && !sameSchema(existing.Schema, registerReq.Schema) {
+			return 0, pinnedIdMismatch{fmt.Sprintf("schema registry holds different schema under id %d", data.Id)}
+		}
+	}
+
+	restoreMode, err := switchToImportMode(subjectName, srClient)
+	if err != nil {
+		return 0, err
+	}
+	pinnedReq := registerReq
+	pinnedReq.Id = data.Id
+	pinnedReq.Version = data.Version
+	schemaId, err := srClient.RegisterSchema(subjectName, pinnedReq)
+	return schemaId, errors.Join(err, restoreMode())
+}
+
+// verifyPins checks if registered schema matches id and version pinned by the resource (if any)
+func verifyPins(data v1beta1.KafkaSchemaData, registered *schemareg.SubjectSchema) error {
+	if data.Id > 0 && registered.Id != data.Id {
+		return pinnedIdMismatch{fmt.Sprintf("schema is registered under id %d, expected %d",
+			registered.Id, data.Id)}
+	}
+	if data.Version > 0 && registered.Version != data.Version {
+		return pinnedIdMismatch{fmt.Sprintf("schema is registered as version %d, expected %d",
+			registered.Version, data.Version)}
+	}
+	return nil
+}
+
+/*
+switchToImportMode switches the subject to IMPORT mode (forcibly, since the subject may be non-empty)
+and returns function restoring its previous subject level mode
+*/
+func switchToImportMode(subjectName string, srClient *schemareg.SrClient) (func() error, error) {
+	previousMode, err := srClient.GetSubjectMode(subjectName)
+	if err != nil {
+		return nil, err
+	}
+	if previousMode == v1beta1.ModeImport {
+		return func() error { return nil }, nil
+	}
+	if err := srClient.SetSubjectMode(subjectName, v1beta1.ModeImport, true); err != nil {
+		return nil, err
+	}
+	return func() error {
+		if len(previousMode) == 0 {
+			return srClient.DeleteSubjectMode(subjectName)
+		}
+		return srClient.SetSubjectMode(subjectName, previousMode, false)
+	}, nil
+}
+
+// sameSchema compares schemas ignoring insignificant whitespaces of JSON (AVRO and JSON) schemas
+func sameSchema(schema string, other string) bool {
+	if schema == other {
+		return true
+	}
+	compacted, otherCompacted := &bytes.Buffer{}, &bytes.Buffer{}
+	return json.Compact(compacted, []byte(schema)) == nil &&
+		json.Compact(otherCompacted, []byte(other)) == nil &&
+		compacted.String() == otherCompacted.String()
+}
@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// wellKnownProtoPrefix is the import path prefix of Protobuf well-known types (bundled with the operator)
+const wellKnownProtoPrefix = "google/protobuf/"
+
+var (
+	protoImportPattern  = regexp.MustCompile(`(?m)(?:^|;)\s*import\s+(?:public\s+|weak\s+)?"([^"]+)"`)
+	protoCommentPattern = regexp.MustCompile(`(?s)//[^\n]*|/\*.*?\*/`)
+)
+
+// protobufFile is .proto file loaded from ConfigMap
+type protobufFile struct {
+	path    string
+	content string
+	imports []string
+}
+
+// protoImports returns import paths of .proto file (ignoring commented out imports)
+func protoImports(content string) []string {
+	var imports []string
+	for _, match := range protoImportPattern.FindAllStringSubmatch(protoCommentPattern.ReplaceAllString(content, ""), -1) {
+		imports = append(imports, match[1])
+	}
+	return imports
+}
+
+// loadProtobufFiles loads .proto files from ConfigMap, keyed by their import paths
+func (r *KafkaSchemaReconciler) loadProtobufFiles(
+	ctx context.Context,
+	namespace string,
+	source *v1beta1.ProtobufFiles) (map[string]*protobufFile, error) {
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: source.ConfigMapName}, configMap); err != nil {
+		return nil, fmt.Errorf("failed to read ConfigMap %s: %w", source.ConfigMapName, err)
+	}
+	if _, ok := configMap.Data[source.Entrypoint]; !ok {
+		return nil, fmt.Errorf("ConfigMap %s has no key %s", source.ConfigMapName, source.Entrypoint)
+	}
+	files := map[string]*protobufFile{}
+	for key, content := range configMap.Data {
+		filePath := protobufFilePath(source, key)
+		files[filePath] = &protobufFile{
+			path:    filePath,
+			content: content,
+			imports: protoImports(content),
+		}
+	}
+	return files, nil
+}
+
+// protobufFilePath returns import path of the file stored under given ConfigMap key
+func protobufFilePath(source *v1beta1.ProtobufFiles, key string) string {
+	if filePath, ok := source.Paths[key]; ok {
+		return filePath
+	}
+	return key
+}
+
+/*
+sortProtobufImports returns files imported (directly or transitively) by the entrypoint in dependency order,
+so every file follows files it imports. Imports missing in the ConfigMap and import cycles are reported as errors.
+Well-known types are left out (see protobufRegistrar)
+*/
+func sortProtobufImports(files map[string]*protobufFile, entrypoint string) ([]*protobufFile, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := map[string]int{}
+	var sorted []*protobufFile
+	var visit func(filePath string, chain []string) error
+	visit = func(filePath string, chain []string) error {
+		switch state[filePath] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("import cycle: %s", strings.Join(append(chain, filePath), " -> "))
+		}
+		file, ok := files[filePath]
+		if !ok {
+			return fmt.Errorf("%s imports %s, which isn't in the ConfigMap", chain[len(chain)-1], filePath)
+		}
+		state[filePath] = visiting
+		for _, imported := range file.imports {
+			if strings.HasPrefix(imported, wellKnownProtoPrefix) {
+				continue
+			}
+			if err := visit(imported, append(chain, filePath)); err != nil {
+				return err
+			}
+		}
+		state[filePath] = visited
+		sorted = append(sorted, file)
+		return nil
+	}
+	if err := visit(entrypoint, nil); err != nil {
+		return nil, err
+	}
+	// entrypoint is the last one
+	return sorted[:len(sorted)-1], nil
+}
+
+// importSubject returns subject imported .proto file is registered under
+func importSubject(source *v1beta1.ProtobufFiles, filePath string) string {
+	name := filePath
+	if source.SubjectNaming == v1beta1.ImportByFileName {
+		name = path.Base(filePath)
+	}
+	return source.SubjectPrefix + name
+}
+
+// protobufRegistrar registers .proto files under their subjects, remembering references of registered files
+type protobufRegistrar struct {
+	srClient  *schemareg.SrClient
+	context   string
+	wellKnown bool
+	// registered maps import paths of registered files to their references
+	registered map[string]schemareg.SchemaReference
+}
+
+/*
+references returns references of imported files registered already.
+Imported well-known types are registered on demand (unless disabled)
+*/
+func (p *protobufRegistrar) references(imports []string) ([]schemareg.SchemaReference, error) {
+	var refs []schemareg.SchemaReference
+	for _, imported := range imports {
+		if _, bundled := wellKnownTypes[imported]; bundled && p.wellKnown {
+			if err := p.registerWellKnownType(imported); err != nil {
+				return nil, err
+			}
+		}
+		if ref, ok := p.registered[imported]; ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+// register registers .proto file under given subject, referencing files it imports
+func (p *protobufRegistrar) register(filePath string, subject string, content string, imports []string) error {
+	refs, err := p.references(imports)
+	if err != nil {
+		return err
+	}
+	req := schemareg.RegisterSchemaReq{
+		Schema:     content,
+		SchemaType: v1beta1.PROTOBUF,
+		References: refs,
+	}
+	if _, err := p.srClient.RegisterSchema(subject, req); err != nil {
+		return fmt.Errorf("failed to register %s under subject %s: %w", filePath, subject, err)
+	}
+	schema, err := p.srClient.LookupSchema(subject, req)
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		return fmt.Errorf("%s isn't registered under subject %s", filePath, subject)
+	}
+	p.registered[filePath] = schemareg.SchemaReference{Name: filePath, Subject: subject, Version: schema.Version}
+	return nil
+}
+
+// registerWellKnownType registers bundled well-known type under subject named by its import path
+func (p *protobufRegistrar) registerWellKnownType(filePath string) error {
+	if _, ok := p.registered[filePath]; ok {
+		return nil
+	}
+	content := wellKnownTypes[filePath]
+	return p.register(filePath, qualifySubject(filePath, p.context), content, protoImports(content))
+}
+
+/*
+registerProtobufReferences registers .proto files imported by PROTOBUF schema and returns references of the schema:
+files imported (directly or transitively) by the entrypoint of multi-file schema are registered under their own
+subjects (in dependency order, each one referencing files it imports), as well as imported well-known types
+(bundled with the operator, unless disabled). Returns nil for other formats
+*/
+func (r *KafkaSchemaReconciler) registerProtobufReferences(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	data v1beta1.KafkaSchemaData,
+	srClient *schemareg.SrClient) ([]schemareg.SchemaReference, error) {
+
+	if data.Format != v1beta1.PROTOBUF {
+		return nil, nil
+	}
+	registrar := &protobufRegistrar{
+		srClient:   srClient,
+		context:    getSchemaContext(res),
+		wellKnown:  registerWellKnownTypes(),
+		registered: map[string]schemareg.SchemaReference{},
+	}
+	source := protobufFilesSource(res)
+	if source == nil {
+		return registrar.references(protoImports(data.Schema))
+	}
+	files, err := r.loadProtobufFiles(ctx, res.Namespace, source)
+	if err != nil {
+		return nil, err
+	}
+	entrypoint := protobufFilePath(source, source.Entrypoint)
+	imports, err := sortProtobufImports(files, entrypoint)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range imports {
+		subject := qualifySubject(importSubject(source, file.path), registrar.context)
+		if err := registrar.register(file.path, subject, file.content, file.imports); err != nil {
+			return nil, err
+		}
+	}
+	// imports of the payload (rather than of the entrypoint file), which may be a historical version of the entrypoint
+	return registrar.references(protoImports(data.Schema))
+}
+
+// protobufFilesSource returns source of multi-file Protobuf schema, nil if it isn't used by the resource
+func protobufFilesSource(res *v1beta1.KafkaSchema) *v1beta1.ProtobufFiles {
+	if res.Spec.Data.SchemaFrom == nil {
+		return nil
+	}
+	return res.Spec.Data.SchemaFrom.ProtobufFiles
+}
+
+/*
+schemaReferences returns references of the schema: references listed in the resource (in their order)
+followed by references of registered Protobuf imports not listed in the resource
+*/
+func (r *KafkaSchemaReconciler) schemaReferences(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	data v1beta1.KafkaSchemaData,
+	srClient *schemareg.SrClient) ([]schemareg.SchemaReference, error) {
+
+	registered, err := r.registerProtobufReferences(ctx, res, data, srClient)
+	if err != nil {
+		return nil, err
+	}
+	var refs []schemareg.SchemaReference
+	listed := map[string]bool{}
+	for _, ref := range data.References {
+		refs = append(refs, schemareg.SchemaReference{Name: ref.Name, Subject: ref.Subject, Version: ref.Version})
+		listed[ref.Name] = true
+	}
+	for _, ref := range registered {
+		if !listed[ref.Name] {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProtoImports(t *testing.T) {
+	content := `syntax = "proto3";
+import "common/money.proto";
+import public "common/currency.proto";
+// import "commented/out.proto";
+/* import "block/comment.proto"; */
+import weak "google/protobuf/timestamp.proto";
+message Order {}`
+	oneLiner := `syntax = "proto3"; import "a.proto"; import "b.proto"; message Order {}`
+
+	imports := protoImports(content)
+	expected := []string{"common/money.proto", "common/currency.proto", "google/protobuf/timestamp.proto"}
+	if !reflect.DeepEqual(imports, expected) {
+		t.Errorf("expected imports %v, got %v", expected, imports)
+	}
+	if imports := protoImports(oneLiner); !reflect.DeepEqual(imports, []string{"a.proto", "b.proto"}) {
+		t.Errorf("expected imports of one-liner [a.proto b.proto], got %v", imports)
+	}
+}
+
+func TestSortProtobufImports(t *testing.T) {
+	filesOf := func(imports map[string][]string) map[string]*protobufFile {
+		files := map[string]*protobufFile{}
+		for filePath, fileImports := range imports {
+			files[filePath] = &protobufFile{path: filePath, imports: fileImports}
+		}
+		return files
+	}
+	tests := []struct {
+		name    string
+		imports map[string][]string
+		sorted  []string
+		err     string
+	}{
+		{"single file", map[string][]string{"order.proto": nil}, nil, ""},
+		{"transitive imports", map[string][]string{
+			"order.proto":           {"common/money.proto", "google/protobuf/timestamp.proto"},
+			"common/money.proto":    {"common/currency.proto"},
+			"common/currency.proto": nil,
+			"unused.proto":          nil,
+		}, []string{"common/currency.proto", "common/money.proto"}, ""},
+		{"shared import", map[string][]string{
+			"order.proto": {"a.proto", "b.proto"},
+			"a.proto":     {"c.proto"},
+			"b.proto":     {"c.proto"},
+			"c.proto":     nil,
+		}, []string{"c.proto", "a.proto", "b.proto"}, ""},
+		{"missing import", map[string][]string{
+			"order.proto": {"missing.proto"},
+		}, nil, "order.proto imports missing.proto, which isn't in the ConfigMap"},
+		{"import cycle", map[string][]string{
+			"order.proto": {"a.proto"},
+			"a.proto":     {"b.proto"},
+			"b.proto":     {"a.proto"},
+		}, nil, "import cycle: order.proto -> a.proto -> b.proto -> a.proto"},
+	}
+	for _, test := range tests {
+		sorted, err := sortProtobufImports(filesOf(test.imports), "order.proto")
+		if len(test.err) > 0 {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: expected error containing %q, got: %v", test.name, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		var sortedPaths []string
+		for _, file := range sorted {
+			sortedPaths = append(sortedPaths, file.path)
+		}
+		if !reflect.DeepEqual(sortedPaths, test.sorted) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.sorted, sortedPaths)
+		}
+	}
+}
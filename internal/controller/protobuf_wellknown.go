@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// wellKnownFs holds bundled definitions of Protobuf well-known types (google/protobuf/*.proto)
+//
+//go:embed wellknown
+var wellKnownFs embed.FS
+
+// wellKnownTypes maps import paths of bundled Protobuf well-known types to their definitions
+var wellKnownTypes = loadWellKnownTypes()
+
+func loadWellKnownTypes() map[string]string {
+	types := map[string]string{}
+	err := fs.WalkDir(wellKnownFs, "wellknown", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := wellKnownFs.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		types[strings.TrimPrefix(filePath, "wellknown/")] = string(content)
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return types
+}
+
+/*
+registerWellKnownTypes tells if Protobuf well-known types imported by schemas are registered automatically
+(REGISTER_WELL_KNOWN_TYPES env var, opt-in). Enabling it registers existing schemas importing well-known types
+as new versions with references
+*/
+func registerWellKnownTypes() bool {
+	return os.Getenv("REGISTER_WELL_KNOWN_TYPES") == "true"
+}
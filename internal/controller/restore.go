@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"sort"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+)
+
+/*
+restoreSoftDeleted reinstates soft-deleted subject (if RestoreSoftDeleted is requested) by re-registering
+its previous versions (along with their references) in order, so schema ids (and order of versions) stay continuous.
+Returns number of restored versions (0 if subject isn't soft-deleted)
+*/
+func restoreSoftDeleted(res *v1beta1.KafkaSchema, srClient *schemareg.SrClient) (int, error) {
+	if !res.Spec.RestoreSoftDeleted {
+		return 0, nil
+	}
+	subjectName := res.Status.Subject
+	latest, err := srClient.GetLatestSchema(subjectName)
+	if err != nil || latest != nil {
+		return 0, err
+	}
+	softDeleted, err := isSoftDeleted(srClient, subjectName)
+	if err != nil || !softDeleted {
+		return 0, err
+	}
+
+	versions, err := srClient.ListVersions(subjectName, true)
+	if err != nil {
+		return 0, err
+	}
+	sort.Ints(versions)
+	restored := 0
+	for _, version := range versions {
+		previous, err := srClient.GetSchemaVersion(subjectName, version, true)
+		if err != nil {
+			return restored, err
+		}
+		if previous == nil {
+			continue
+		}
+		_, err = srClient.RegisterSchema(subjectName, schemareg.RegisterSchemaReq{
+			Schema:     previous.Schema,
+			SchemaType: previous.SchemaType,
+			Metadata:   previous.Metadata,
+			RuleSet:    previous.RuleSet,
+			References: previous.References,
+		})
+		if err != nil {
+			return restored, err
+		}
+		restored++
+	}
+	return restored, nil
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// approveRollbackAnnotation (set to the version rolled back to) approves rollback of the subject
+const approveRollbackAnnotation = "kafka.incubly.oss/approve-rollback"
+
+/*
+rollbackVersions returns versions of the subject newer than the previously registered version matching the schema,
+if the resource asks for rollback (DeleteNewerVersions policy). Empty if the matching version is the latest one
+*/
+func rollbackVersions(
+	subjectName string,
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient,
+	previouslyRegistered *schemareg.SubjectSchema) ([]int, error) {
+
+	if res.Spec.RollbackPolicy != v1beta1.RollbackDeleteNewerVersions || previouslyRegistered == nil {
+		return nil, nil
+	}
+	versions, err := srClient.ListVersions(subjectName, false)
+	if err != nil {
+		return nil, err
+	}
+	var newer []int
+	for _, version := range versions {
+		if version > previouslyRegistered.Version {
+			newer = append(newer, version)
+		}
+	}
+	return newer, nil
+}
+
+func rollbackApproved(res *v1beta1.KafkaSchema, version int) bool {
+	return res.Annotations[approveRollbackAnnotation] == strconv.Itoa(version)
+}
+
+/*
+rollbackPending reports rollback awaiting approval, leaving the subject untouched.
+Reconciliation is requeued (without failing) until the rollback is approved
+*/
+func (r *KafkaSchemaReconciler) rollbackPending(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	logger logr.Logger,
+	version int,
+	newer []int) (ctrl.Result, error) {
+
+	msg := fmt.Sprintf("Rolling subject %s back to version %d requires deletion of version(s) %v, "+
+		"approve it by annotating the resource with %s=%d",
+		res.Status.Subject, version, newer, approveRollbackAnnotation, version)
+	logger.Info(msg)
+	r.Recorder.Event(res, corev1.EventTypeWarning, "RollbackPending", msg)
+	res.SetReadyReason(v1beta1.RollbackPending, msg)
+	res.Status.Healthy = false
+	res.Status.Status = "False"
+	if err := r.Status().Update(ctx, res); err != nil {
+		logger.Error(err, "failed to update resource status")
+		return ctrl.Result{}, err
+	}
+	return r.requeue(), nil
+}
+
+/*
+rollback soft-deletes versions newer than the one rolled back to (newest first), so it becomes the latest version.
+Approval is consumed (the annotation is removed), so further rollbacks have to be approved again
+*/
+func (r *KafkaSchemaReconciler) rollback(
+	ctx context.Context,
+	subjectName string,
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient,
+	logger logr.Logger,
+	version int,
+	newer []int) error {
+
+	for i := len(newer) - 1; i >= 0; i-- {
+		if err := srClient.DeleteSubjectVersion(subjectName, newer[i], false); err != nil {
+			return err
+		}
+	}
+	err := r.patchAnnotations(ctx, res, func(annotations map[string]string) {
+		delete(annotations, approveRollbackAnnotation)
+	})
+	if err != nil {
+		return err
+	}
+	res.Status.RegisteredVersions = slices.DeleteFunc(res.Status.RegisteredVersions, func(v int) bool {
+		return slices.Contains(newer, v)
+	})
+	msg := fmt.Sprintf("Rolled subject %s back to version %d, soft-deleted version(s) %v", subjectName, version, newer)
+	logger.Info(msg)
+	r.Recorder.Event(res, corev1.EventTypeNormal, "RolledBack", msg)
+	return nil
+}
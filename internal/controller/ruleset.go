@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+
+	"github.com/google/cel-go/cel"
+)
+
+var (
+	migrationRuleModes = []v1beta1.RuleMode{v1beta1.RuleUpgrade, v1beta1.RuleDowngrade, v1beta1.RuleUpDown}
+	domainRuleModes    = []v1beta1.RuleMode{v1beta1.RuleWrite, v1beta1.RuleRead, v1beta1.RuleWriteRead}
+)
+
+/*
+validateRuleSet verifies the rule set before it's registered: rule names must be unique,
+modes must match the kind of the rule set (migration or domain) and CEL expressions must be syntactically valid.
+Expressions of other rule types (e.g. JSONATA) are left to schema registry and serializers
+*/
+func validateRuleSet(ruleSet *v1beta1.RuleSet) error {
+	if ruleSet == nil {
+		return nil
+	}
+	env, err := cel.NewEnv()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	names := map[string]bool{}
+	validate := func(rules []v1beta1.Rule, modes []v1beta1.RuleMode, ruleSetKind string) {
+		for _, rule := range rules {
+			if names[rule.Name] {
+				errs = append(errs, fmt.Errorf("rule %s: duplicate rule name", rule.Name))
+			}
+			names[rule.Name] = true
+			if !slices.Contains(modes, rule.Mode) {
+				errs = append(errs, fmt.Errorf("rule %s: mode %s isn't supported by %s rules",
+					rule.Name, rule.Mode, ruleSetKind))
+			}
+			if err := validateCelExpression(env, rule); err != nil {
+				errs = append(errs, fmt.Errorf("rule %s: %w", rule.Name, err))
+			}
+		}
+	}
+	validate(ruleSet.MigrationRules, migrationRuleModes, "migration")
+	validate(ruleSet.DomainRules, domainRuleModes, "domain")
+	return errors.Join(errs...)
+}
+
+/*
+validateCelExpression parses expression of CEL and CEL_FIELD rules,
+including optional guard (separated from the expression with ";").
+Expressions are only parsed, since types of message fields are known to serializers only
+*/
+func validateCelExpression(env *cel.Env, rule v1beta1.Rule) error {
+	if rule.Type != "CEL" && rule.Type != "CEL_FIELD" {
+		return nil
+	}
+	if len(strings.TrimSpace(rule.Expr)) == 0 {
+		return fmt.Errorf("%s rule requires expression", rule.Type)
+	}
+	for _, expr := range splitCelGuard(rule.Expr) {
+		if _, issues := env.Parse(expr); issues != nil && issues.Err() != nil {
+			return fmt.Errorf("invalid CEL expression: %w", issues.Err())
+		}
+	}
+	return nil
+}
+
+// splitCelGuard splits guard from CEL expression at first ";" outside of string literals
+func splitCelGuard(expr string) []string {
+	var quote rune
+	escaped := false
+	for i, c := range expr {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && c == '\\':
+			escaped = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == ';':
+			return []string{expr[:i], expr[i+1:]}
+		}
+	}
+	return []string{expr}
+}
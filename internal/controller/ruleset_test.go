@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+)
+
+func TestValidateRuleSet(t *testing.T) {
+	celRule := func(name string, mode v1beta1.RuleMode, expr string) v1beta1.Rule {
+		return v1beta1.Rule{Name: name, Kind: v1beta1.RuleCondition, Mode: mode, Type: "CEL", Expr: expr}
+	}
+	tests := []struct {
+		name    string
+		ruleSet *v1beta1.RuleSet
+		err     string
+	}{
+		{"no rule set", nil, ""},
+		{"valid CEL rule", &v1beta1.RuleSet{
+			DomainRules: []v1beta1.Rule{celRule("checkSsn", v1beta1.RuleWrite, "size(message.ssn) == 9")},
+		}, ""},
+		{"valid CEL_FIELD rule with guard", &v1beta1.RuleSet{
+			DomainRules: []v1beta1.Rule{{Name: "mask", Kind: v1beta1.RuleTransform, Mode: v1beta1.RuleWrite,
+				Type: "CEL_FIELD", Expr: `name == "ssn" ; value + "-masked"`}},
+		}, ""},
+		{"valid CEL rule with quoted semicolon", &v1beta1.RuleSet{
+			DomainRules: []v1beta1.Rule{celRule("checkName", v1beta1.RuleWrite, `message.name == "a;b"`)},
+		}, ""},
+		{"valid CEL_FIELD rule with guard containing quoted semicolon", &v1beta1.RuleSet{
+			DomainRules: []v1beta1.Rule{{Name: "mask", Kind: v1beta1.RuleTransform, Mode: v1beta1.RuleWrite,
+				Type: "CEL_FIELD", Expr: `name == 'a;\'b' ; value + ";masked"`}},
+		}, ""},
+		{"JSONATA migration rule", &v1beta1.RuleSet{
+			MigrationRules: []v1beta1.Rule{{Name: "upgrade", Kind: v1beta1.RuleTransform, Mode: v1beta1.RuleUpgrade,
+				Type: "JSONATA", Expr: "$merge([$sift($, function($v, $k) {$k != 'size'}), {'height': $.'size'}])"}},
+		}, ""},
+		{"invalid CEL expression", &v1beta1.RuleSet{
+			DomainRules: []v1beta1.Rule{celRule("checkSsn", v1beta1.RuleWrite, "size(message.ssn) ==")},
+		}, "rule checkSsn: invalid CEL expression"},
+		{"missing CEL expression", &v1beta1.RuleSet{
+			DomainRules: []v1beta1.Rule{celRule("checkSsn", v1beta1.RuleWrite, "")},
+		}, "rule checkSsn: CEL rule requires expression"},
+		{"migration mode of domain rule", &v1beta1.RuleSet{
+			DomainRules: []v1beta1.Rule{celRule("checkSsn", v1beta1.RuleUpgrade, "true")},
+		}, "mode UPGRADE isn't supported by domain rules"},
+		{"duplicate rule names", &v1beta1.RuleSet{
+			DomainRules: []v1beta1.Rule{
+				celRule("check", v1beta1.RuleWrite, "true"),
+				celRule("check", v1beta1.RuleRead, "true"),
+			},
+		}, "rule check: duplicate rule name"},
+	}
+	for _, test := range tests {
+		err := validateRuleSet(test.ruleSet)
+		if len(test.err) == 0 && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+		if len(test.err) > 0 && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("%s: expected error containing %q, got: %v", test.name, test.err, err)
+		}
+	}
+}
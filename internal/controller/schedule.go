@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+/*
+scheduleCleanup records scheduled cleanup time of deleted resource (if CleanupDelay is requested)
+and returns time remaining until cleanup may be performed (0 if it's due)
+*/
+func (r *KafkaSchemaReconciler) scheduleCleanup(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	logger logr.Logger) (time.Duration, error) {
+
+	if res.Spec.CleanupDelay == nil || getCleanupPolicy(res) == v1beta1.DISABLED {
+		return 0, nil
+	}
+	if res.Status.ScheduledCleanupTime == nil {
+		scheduled := metav1.NewTime(res.GetDeletionTimestamp().Add(res.Spec.CleanupDelay.Duration))
+		res.Status.ScheduledCleanupTime = &scheduled
+	}
+	remaining := time.Until(res.Status.ScheduledCleanupTime.Time)
+	if remaining <= 0 {
+		return 0, nil
+	}
+	msg := fmt.Sprintf("Schema registry cleanup scheduled at %s",
+		res.Status.ScheduledCleanupTime.UTC().Format(time.RFC3339))
+	logger.Info(msg)
+	res.SetReadyReason(v1beta1.CleanupScheduled, msg)
+	return remaining, r.Status().Update(ctx, res)
+}
+
+// ttlRemaining returns time remaining until the resource outlives its Ttl (if requested)
+func ttlRemaining(res *v1beta1.KafkaSchema) (time.Duration, bool) {
+	if res.Spec.Ttl == nil {
+		return 0, false
+	}
+	return time.Until(res.GetCreationTimestamp().Add(res.Spec.Ttl.Duration)), true
+}
+
+// requeueBeforeExpiry makes sure the resource with Ttl is requeued no later than its expiry
+func requeueBeforeExpiry(res *v1beta1.KafkaSchema, result ctrl.Result) ctrl.Result {
+	remaining, ok := ttlRemaining(res)
+	if !ok || remaining <= 0 {
+		return result
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > remaining {
+		result.RequeueAfter = remaining
+	}
+	return result
+}
+
+/*
+ttlExpired tells if the resource outlived its Ttl and should be deleted. Resources protected from deletion are kept
+(deletion would be rejected by the webhook) until they're unlocked, with "TtlExpired" condition reporting it
+*/
+func (r *KafkaSchemaReconciler) ttlExpired(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	logger logr.Logger) (bool, error) {
+
+	remaining, ok := ttlRemaining(res)
+	if !ok || remaining > 0 {
+		res.RemoveCondition("TtlExpired")
+		return false, nil
+	}
+	if r.DeletionProtection == nil {
+		return true, nil
+	}
+	protected, err := r.DeletionProtection.Protects(ctx, res)
+	if err != nil {
+		return false, err
+	}
+	if !protected {
+		return true, nil
+	}
+	msg := fmt.Sprintf("Resource outlived its TTL (%s), but it's protected from deletion, annotate it with %s=true "+
+		"to delete it", res.Spec.Ttl.Duration, v1beta1.UnlockDeletionAnnotation)
+	if res.SetCondition("TtlExpired", v1beta1.DeletionProtected, msg) {
+		logger.Info(msg)
+		r.Recorder.Event(res, corev1.EventTypeWarning, "TtlExpired", msg)
+	}
+	return false, nil
+}
+
+// deleteExpired deletes the resource which outlived its Ttl (regular cleanup follows)
+func (r *KafkaSchemaReconciler) deleteExpired(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	logger logr.Logger) (ctrl.Result, error) {
+
+	logger.Info("KafkaSchema CR outlived its TTL, deleting")
+	r.Recorder.Event(res, corev1.EventTypeNormal, "TtlExpired",
+		fmt.Sprintf("Resource outlived its TTL (%s) and is deleted", res.Spec.Ttl.Duration))
+	if err := r.Delete(ctx, res); err != nil {
+		return r.logError(logger, err, ctx, res,
+			v1beta1.ResourceUpdate,
+			"Failed to delete expired KafkaSchema CR")
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
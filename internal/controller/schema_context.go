@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"os"
+	"strings"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+)
+
+/*
+getSchemaContext returns schema registry context of the resource: defined by the resource itself,
+mapped to its namespace (SCHEMA_REGISTRY_NAMESPACE_CONTEXTS, e.g. "team-a=.team-a,team-b=.team-b")
+or operator default (DEFAULT_SCHEMA_REGISTRY_CONTEXT). Empty context (or ".") stands for default context
+*/
+func getSchemaContext(res *v1beta1.KafkaSchema) string {
+	if len(res.Spec.SchemaRegistry.Context) > 0 {
+		return res.Spec.SchemaRegistry.Context
+	}
+	for _, mapping := range strings.Split(os.Getenv("SCHEMA_REGISTRY_NAMESPACE_CONTEXTS"), ",") {
+		namespace, context, found := strings.Cut(strings.TrimSpace(mapping), "=")
+		if found && namespace == res.Namespace {
+			return context
+		}
+	}
+	return os.Getenv("DEFAULT_SCHEMA_REGISTRY_CONTEXT")
+}
+
+/*
+qualifySubject returns subject name qualified with schema context (e.g. ":.team-a:subject").
+Subjects in default context (and already qualified subjects) are returned as they are
+*/
+func qualifySubject(subject string, context string) string {
+	context = strings.TrimPrefix(context, ".")
+	if len(context) == 0 || strings.HasPrefix(subject, ":.") {
+		return subject
+	}
+	return ":." + context + ":" + subject
+}
+
+// explicitContext returns schema context as defined on the resource, with "." standing for default context
+func explicitContext(context string) string {
+	if len(strings.TrimPrefix(context, ".")) == 0 {
+		return "."
+	}
+	return context
+}
@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+/*
+resolveSchemaData returns schema data of the resource with schema payload loaded from .data.schemaFrom
+or serialized from .data.schemaObject (if used).
+Payload of multi-file Protobuf schema is its entrypoint (imported files are registered separately).
+Resolved payload is never written back to the resource
+*/
+func (r *KafkaSchemaReconciler) resolveSchemaData(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema) (v1beta1.KafkaSchemaData, error) {
+
+	data := res.Spec.Data
+	if data.SchemaObject != nil {
+		if len(data.Schema) > 0 || data.SchemaFrom != nil {
+			return data, fmt.Errorf("schemaObject is mutually exclusive with schema and schemaFrom")
+		}
+		var err error
+		data.Schema, err = serializeSchemaObject(data.SchemaObject)
+		return data, err
+	}
+	source := data.SchemaFrom
+	if source == nil {
+		return data, nil
+	}
+	if len(data.Schema) > 0 {
+		return data, fmt.Errorf("schema and schemaFrom are mutually exclusive")
+	}
+	var err error
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		data.Schema, err = r.readConfigMapKey(ctx, res.Namespace, source.ConfigMapKeyRef)
+	case source.SecretKeyRef != nil:
+		data.Schema, err = r.readSecretKey(ctx, res.Namespace, source.SecretKeyRef)
+	case source.ProtobufFiles != nil:
+		if data.Format != v1beta1.PROTOBUF {
+			return data, fmt.Errorf("protobufFiles require PROTOBUF format")
+		}
+		data.Schema, err = r.readConfigMapKey(ctx, res.Namespace, &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: source.ProtobufFiles.ConfigMapName},
+			Key:                  source.ProtobufFiles.Entrypoint,
+		})
+	default:
+		err = fmt.Errorf("schemaFrom requires one of configMapKeyRef, secretKeyRef and protobufFiles")
+	}
+	return data, err
+}
+
+/*
+serializeSchemaObject serializes structured schema payload to JSON deterministically:
+object keys are sorted, numbers keep their precision and HTML characters aren't escaped
+*/
+func serializeSchemaObject(schemaObject *apiextensionsv1.JSON) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(schemaObject.Raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("invalid schemaObject: %w", err)
+	}
+	serialized := &bytes.Buffer{}
+	encoder := json.NewEncoder(serialized)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("failed to serialize schemaObject: %w", err)
+	}
+	return strings.TrimSuffix(serialized.String(), "\n"), nil
+}
+
+// schemaContentHash returns SHA-256 hash of schema payload (hex encoded)
+func schemaContentHash(schema string) string {
+	hash := sha256.Sum256([]byte(schema))
+	return hex.EncodeToString(hash[:])
+}
+
+/*
+registeredContentHash returns content hash of schema payload along with its references, so that changed files
+imported by multi-file Protobuf schema (registered as new versions of referenced subjects) change the hash as well.
+Hash of schema without references is hash of its payload
+*/
+func registeredContentHash(schema string, references []schemareg.SchemaReference) string {
+	if len(references) == 0 {
+		return schemaContentHash(schema)
+	}
+	content := schema
+	for _, ref := range references {
+		content += fmt.Sprintf("\n%s %s %d", ref.Name, ref.Subject, ref.Version)
+	}
+	return schemaContentHash(content)
+}
+
+// readConfigMapKey returns value of ConfigMap key selected in given namespace
+func (r *KafkaSchemaReconciler) readConfigMapKey(
+	ctx context.Context,
+	namespace string,
+	selector *corev1.ConfigMapKeySelector) (string, error) {
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: selector.Name}, configMap); err != nil {
+		return "", fmt.Errorf("failed to read ConfigMap %s: %w", selector.Name, err)
+	}
+	value, ok := configMap.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("ConfigMap %s has no key %s", selector.Name, selector.Key)
+	}
+	return value, nil
+}
+
+// readSecretKey returns value of Secret key selected in given namespace
+func (r *KafkaSchemaReconciler) readSecretKey(
+	ctx context.Context,
+	namespace string,
+	selector *corev1.SecretKeySelector) (string, error) {
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: selector.Name}, secret); err != nil {
+		return "", fmt.Errorf("failed to read Secret %s: %w", selector.Name, err)
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return "", fmt.Errorf("Secret %s has no key %s", selector.Name, selector.Key)
+	}
+	return string(value), nil
+}
+
+// referencedConfigMaps returns names of ConfigMaps the resource loads schemas from (including imports and version history)
+func referencedConfigMaps(res *v1beta1.KafkaSchema) []string {
+	var names []string
+	if source := res.Spec.Data.SchemaFrom; source != nil && source.ConfigMapKeyRef != nil {
+		names = append(names, source.ConfigMapKeyRef.Name)
+	}
+	if source := protobufFilesSource(res); source != nil {
+		names = append(names, source.ConfigMapName)
+	}
+	for _, ref := range res.Spec.Data.ImportsFrom {
+		names = append(names, ref.Name)
+	}
+	for _, version := range res.Spec.Versions {
+		if version.ConfigMapKeyRef != nil {
+			names = append(names, version.ConfigMapKeyRef.Name)
+		}
+	}
+	return names
+}
+
+// referencedSecrets returns names of Secrets the resource loads schemas from
+func referencedSecrets(res *v1beta1.KafkaSchema) []string {
+	if source := res.Spec.Data.SchemaFrom; source != nil && source.SecretKeyRef != nil {
+		return []string{source.SecretKeyRef.Name}
+	}
+	return nil
+}
+
+/*
+schemasReferencing returns function mapping ConfigMaps (or Secrets) to reconcile requests
+of KafkaSchemas (in their namespace) loading schemas from them
+*/
+func (r *KafkaSchemaReconciler) schemasReferencing(
+	referenced func(*v1beta1.KafkaSchema) []string) func(context.Context, client.Object) []reconcile.Request {
+
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		schemas := &v1beta1.KafkaSchemaList{}
+		if err := r.List(ctx, schemas, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list KafkaSchema CRs referencing "+obj.GetName())
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, schema := range schemas.Items {
+			if slices.Contains(referenced(&schema), obj.GetName()) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: schema.Namespace, Name: schema.Name},
+				})
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,325 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+
+	"github.com/go-logr/logr"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// SchemaRegistryImportReconciler reconciles a SchemaRegistryImport object
+type SchemaRegistryImportReconciler struct {
+	RequeueDelay time.Duration
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=kafka.incubly.oss,resources=schemaregistryimports,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=kafka.incubly.oss,resources=schemaregistryimports/status,verbs=get;update;patch
+
+const importedByLabel = "kafka.incubly.oss/imported-by"
+
+// Reconcile imports schema registry subjects as KafkaSchema resources.
+// It's the opt-in counterpart of KafkaSchemaReconciler: it periodically lists
+// subjects matching SubjectPattern and creates KafkaSchema resources
+// for subjects that don't have their representation in Kube yet.
+// Existing KafkaSchema resources are never modified nor deleted,
+// so that they can be handed over to GitOps incrementally.
+func (r *SchemaRegistryImportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+
+	imp := &v1beta1.SchemaRegistryImport{}
+	err := r.Get(ctx, req.NamespacedName, imp)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.FromContext(ctx).Info("SchemaRegistryImport CR not found. I can't do anything about it...")
+			return ctrl.Result{}, nil
+		}
+		log.FromContext(ctx).Error(err, "Failed to get SchemaRegistryImport CR")
+		return ctrl.Result{}, err
+	}
+
+	logger := log.FromContext(
+		ctx,
+		"resource::name", imp.Name,
+		"resource::uid", imp.UID,
+	)
+
+	spec := imp.Spec
+	srClient, err := schemareg.NewClient(&spec.SchemaRegistry, logger)
+	if err != nil {
+		return r.logError(logger, err, ctx, imp,
+			v1beta1.SchemaRegistryClient,
+			"Failed to instantiate Schema Registry Client")
+	}
+	imp.Status.SchemaRegistryUrl = srClient.BaseUrl.String()
+
+	subjectPattern, err := regexp.Compile(spec.SubjectPattern)
+	if err != nil {
+		return r.logError(logger, err, ctx, imp,
+			v1beta1.ImportSubjects,
+			"Failed to compile subject pattern")
+	}
+	nameTemplate, err := template.New("name").Parse(spec.NameTemplate)
+	if err != nil {
+		return r.logError(logger, err, ctx, imp,
+			v1beta1.ImportSubjects,
+			"Failed to parse name template")
+	}
+
+	schemaContext := importContext(imp)
+	contextPrefix := qualifySubject("", schemaContext)
+	subjects, err := srClient.ListSubjects(contextPrefix, false)
+	if err != nil {
+		return r.logError(logger, err, ctx, imp,
+			v1beta1.ImportSubjects,
+			"Failed to list subjects in schema registry")
+	}
+
+	var imported []v1beta1.ImportedSubject
+	for _, subject := range subjects {
+		// pattern and name template apply to subject name within its context
+		subjectName := strings.TrimPrefix(subject, contextPrefix)
+		if !subjectPattern.MatchString(subjectName) {
+			continue
+		}
+		resourceName, err := renderResourceName(nameTemplate, subjectName)
+		if err != nil {
+			return r.logError(logger, err, ctx, imp,
+				v1beta1.ImportSubjects,
+				"Failed to render resource name for subject "+subject)
+		}
+		resourceName, err = r.importSubject(ctx, imp, srClient, schemaContext, subject, resourceName, logger)
+		if err != nil {
+			return r.logError(logger, err, ctx, imp,
+				v1beta1.CreateResource,
+				"Failed to create KafkaSchema CR for subject "+subject)
+		}
+		imported = append(imported, v1beta1.ImportedSubject{Subject: subject, Resource: resourceName})
+	}
+
+	imp.Status.ImportedSubjects = imported
+	imp.Status.LastImportTsEpoch = time.Now().UnixMilli()
+	imp.SetReadyReason(v1beta1.Complete, fmt.Sprintf("Imported %d subjects", len(imported)))
+	if err := r.Status().Update(ctx, imp); err != nil {
+		logger.Error(err, "Failed to update status of successful import")
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("SchemaRegistryImport CR successfully reconciled")
+	// subjects are re-imported periodically, following requeue delay of KafkaSchemas (see KafkaSchemaReconciler.requeue)
+	return ctrl.Result{Requeue: r.RequeueDelay >= 0, RequeueAfter: r.RequeueDelay}, nil
+}
+
+/*
+importContext returns schema registry context subjects are imported from: the one of the import,
+or the one KafkaSchema resources in target namespace fall back to
+*/
+func importContext(imp *v1beta1.SchemaRegistryImport) string {
+	return getSchemaContext(&v1beta1.KafkaSchema{
+		ObjectMeta: metav1.ObjectMeta{Namespace: targetNamespace(imp)},
+		Spec:       v1beta1.KafkaSchemaSpec{SchemaRegistry: imp.Spec.SchemaRegistry},
+	})
+}
+
+// targetNamespace returns namespace KafkaSchema resources are imported to
+func targetNamespace(imp *v1beta1.SchemaRegistryImport) string {
+	if len(imp.Spec.TargetNamespace) > 0 {
+		return imp.Spec.TargetNamespace
+	}
+	return imp.Namespace
+}
+
+/*
+importSubject creates KafkaSchema resource representing current state of the subject
+(latest schema, its references and subject-level compatibility mode), unless resource already exists.
+Returns name of the resource representing the subject: if resource name is taken by resource of another subject
+(e.g. subjects "a.b" and "a-b" sanitized to the same name), it's suffixed with hash of the subject
+*/
+func (r *SchemaRegistryImportReconciler) importSubject(
+	ctx context.Context,
+	imp *v1beta1.SchemaRegistryImport,
+	srClient *schemareg.SrClient,
+	schemaContext string,
+	subject string,
+	resourceName string,
+	logger logr.Logger) (string, error) {
+
+	namespace := targetNamespace(imp)
+	for _, name := range []string{resourceName, disambiguateResourceName(resourceName, subject)} {
+		existing := &v1beta1.KafkaSchema{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, existing)
+		if errors.IsNotFound(err) {
+			return name, r.createImported(ctx, imp, srClient, schemaContext, subject, namespace, name, logger)
+		} else if err != nil {
+			return "", err
+		}
+		if representsSubject(existing, subject) {
+			// already imported (or managed independently)
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("resource name %s is taken by KafkaSchema of another subject", resourceName)
+}
+
+func (r *SchemaRegistryImportReconciler) createImported(
+	ctx context.Context,
+	imp *v1beta1.SchemaRegistryImport,
+	srClient *schemareg.SrClient,
+	schemaContext string,
+	subject string,
+	namespace string,
+	resourceName string,
+	logger logr.Logger) error {
+
+	latest, err := srClient.GetLatestSchema(subject)
+	if err != nil {
+		return err
+	}
+	if latest == nil {
+		return fmt.Errorf("subject %s disappeared from schema registry", subject)
+	}
+	config, err := srClient.GetSubjectConfig(subject)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		config = &schemareg.SubjectConfigRes{}
+	}
+
+	res := &v1beta1.KafkaSchema{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				importedByLabel: imp.Name,
+			},
+		},
+		Spec: v1beta1.KafkaSchemaSpec{
+			SubjectName:    strings.TrimPrefix(subject, qualifySubject("", schemaContext)),
+			SchemaRegistry: imp.Spec.SchemaRegistry,
+			Adoption:       v1beta1.AdoptIfMatches,
+			Data: v1beta1.KafkaSchemaData{
+				Schema:        latest.Schema,
+				Format:        schemaFormat(latest.SchemaType),
+				Compatibility: config.CompatibilityLevel,
+			},
+		},
+	}
+	// context is explicit, so that the resource keeps representing the subject if operator defaults change
+	res.Spec.SchemaRegistry.Context = explicitContext(schemaContext)
+	for _, ref := range latest.References {
+		res.Spec.Data.References = append(res.Spec.Data.References,
+			v1beta1.SchemaReference{Name: ref.Name, Subject: ref.Subject, Version: ref.Version})
+	}
+	if !reflect.ValueOf(config.SubjectConfig).IsZero() {
+		res.Spec.SubjectConfig = &config.SubjectConfig
+	}
+	if getImportMode(imp) == v1beta1.ImportReadOnly {
+		res.Spec.DriftPolicy = v1beta1.DriftReport
+		res.Spec.CleanupPolicy = v1beta1.DISABLED
+	}
+
+	if err := r.Create(ctx, res); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Imported subject %s as KafkaSchema %s/%s", subject, namespace, resourceName))
+	return nil
+}
+
+func (r *SchemaRegistryImportReconciler) logError(
+	logger logr.Logger,
+	err error,
+	ctx context.Context,
+	imp *v1beta1.SchemaRegistryImport,
+	reason v1beta1.ReadyReason,
+	msg string,
+) (ctrl.Result, error) {
+	logger.Error(err, msg)
+	imp.Status.LastImportTsEpoch = time.Now().UnixMilli()
+	if imp.SetReadyReason(reason, msg) {
+		// ignoring the update error - we should return the actual root cause instead
+		_ = r.Status().Update(ctx, imp)
+	}
+	return ctrl.Result{}, err
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+func renderResourceName(nameTemplate *template.Template, subject string) (string, error) {
+	var name bytes.Buffer
+	if err := nameTemplate.Execute(&name, struct{ Subject string }{subject}); err != nil {
+		return "", err
+	}
+	rendered := name.String()
+	if len(rendered) == 0 {
+		rendered = subject
+	}
+	return notBlank(sanitizeResourceName(rendered), "resource name of subject "+subject)
+}
+
+// sanitizeResourceName converts arbitrary string to valid resource name (DNS subdomain)
+func sanitizeResourceName(name string) string {
+	sanitized := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if len(sanitized) > 253 {
+		sanitized = strings.TrimRight(sanitized[:253], "-.")
+	}
+	return sanitized
+}
+
+/*
+representsSubject tells if existing resource represents given (qualified) subject
+(subject name of imported resource is explicit)
+*/
+func representsSubject(res *v1beta1.KafkaSchema, subject string) bool {
+	return qualifySubject(res.Spec.SubjectName, getSchemaContext(res)) == subject || res.Status.Subject == subject
+}
+
+// disambiguateResourceName suffixes resource name with (short) hash of the subject
+func disambiguateResourceName(resourceName string, subject string) string {
+	hash := schemaContentHash(subject)[:8]
+	if len(resourceName) > 253-len(hash)-1 {
+		resourceName = strings.TrimRight(resourceName[:253-len(hash)-1], "-.")
+	}
+	return resourceName + "-" + hash
+}
+
+// schemaFormat maps schemaType returned by registry (which omits it for AVRO) to SchemaFormat
+func schemaFormat(schemaType v1beta1.SchemaFormat) v1beta1.SchemaFormat {
+	if len(schemaType) == 0 {
+		return v1beta1.AVRO
+	}
+	return schemaType
+}
+
+func getImportMode(imp *v1beta1.SchemaRegistryImport) v1beta1.ImportMode {
+	if len(imp.Spec.Mode) > 0 {
+		return imp.Spec.Mode
+	}
+	return v1beta1.ImportReadOnly
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SchemaRegistryImportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta1.SchemaRegistryImport{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
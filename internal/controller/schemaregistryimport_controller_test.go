@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("SchemaRegistryImport Controller", func() {
+
+	BeforeEach(func() {
+		srMock.Clear()
+	})
+
+	ctx := context.Background()
+	Context("Importing subjects", func() {
+		It("Should create read-only KafkaSchemas for subjects matching pattern", func() {
+			prefix := fmt.Sprintf("legacy%d", time.Now().UnixMilli())
+			By("Given subjects were registered directly in registry")
+			srMock.RegisterSchema(prefix+"-a-value", `"string"`)
+			srMock.Subjects[prefix+"-a-value"].CompatibilityMode = v1beta1.FULL
+			srMock.RegisterSchema("other-value", `"int"`)
+
+			By("When importing subjects matching pattern")
+			anImport := anImportWithMode(v1beta1.ImportReadOnly, "^"+prefix+"-")
+			Ω(whenImportingSubjects(ctx, anImport)).ShouldNot(BeNil())
+
+			By("Then KafkaSchema should be created for matching subject")
+			imported := &v1beta1.KafkaSchema{}
+			Expect(k8sClient.Get(ctx,
+				types.NamespacedName{Namespace: "default", Name: "imported-" + prefix + "-a-value"},
+				imported)).Should(Succeed())
+			Expect(imported.Spec.SubjectName).Should(Equal(prefix + "-a-value"))
+			Expect(imported.Spec.SchemaRegistry.Context).Should(Equal("."))
+			Expect(imported.Spec.Data.Schema).Should(Equal(`"string"`))
+			Expect(imported.Spec.Data.Format).Should(Equal(v1beta1.AVRO))
+			Expect(imported.Spec.Data.Compatibility).Should(Equal(v1beta1.FULL))
+			Expect(imported.Labels).Should(HaveKeyWithValue(importedByLabel, anImport.Name))
+
+			By("And it should be read-only")
+			Expect(imported.Spec.Adoption).Should(Equal(v1beta1.AdoptIfMatches))
+			Expect(imported.Spec.DriftPolicy).Should(Equal(v1beta1.DriftReport))
+			Expect(imported.Spec.CleanupPolicy).Should(Equal(v1beta1.DISABLED))
+
+			By("And only matching subject should be reported in status")
+			current := &v1beta1.SchemaRegistryImport{}
+			Expect(k8sClient.Get(ctx, namespacedImportName(anImport), current)).Should(Succeed())
+			Expect(current.Status.ImportedSubjects).Should(Equal([]v1beta1.ImportedSubject{
+				{Subject: prefix + "-a-value", Resource: "imported-" + prefix + "-a-value"},
+			}))
+		})
+		It("Should create managed KafkaSchemas if mode is Managed", func() {
+			prefix := fmt.Sprintf("legacy%d", time.Now().UnixMilli())
+			By("Given subject was registered directly in registry")
+			srMock.RegisterSchema(prefix+"-b-value", `"string"`)
+
+			By("When importing subjects")
+			anImport := anImportWithMode(v1beta1.ImportManaged, "^"+prefix+"-")
+			Ω(whenImportingSubjects(ctx, anImport)).ShouldNot(BeNil())
+
+			By("Then KafkaSchema should use default policies")
+			imported := &v1beta1.KafkaSchema{}
+			Expect(k8sClient.Get(ctx,
+				types.NamespacedName{Namespace: "default", Name: "imported-" + prefix + "-b-value"},
+				imported)).Should(Succeed())
+			Expect(imported.Spec.DriftPolicy).Should(BeEmpty())
+			Expect(imported.Spec.CleanupPolicy).Should(BeEmpty())
+		})
+		It("Should not modify existing KafkaSchemas", func() {
+			prefix := fmt.Sprintf("legacy%d", time.Now().UnixMilli())
+			By("Given subject was registered directly in registry")
+			srMock.RegisterSchema(prefix+"-c-value", `"string"`)
+			By("And KafkaSchema of the subject with the same name already exists")
+			existing := aSchemaWithCleanupPolicy(v1beta1.SOFT)
+			existing.Name = "imported-" + prefix + "-c-value"
+			existing.Spec.SubjectName = prefix + "-c-value"
+			Expect(k8sClient.Create(ctx, existing)).Should(Succeed())
+
+			By("When importing subjects")
+			anImport := anImportWithMode(v1beta1.ImportReadOnly, "^"+prefix+"-")
+			Ω(whenImportingSubjects(ctx, anImport)).ShouldNot(BeNil())
+
+			By("Then existing KafkaSchema should be left untouched")
+			current := &v1beta1.KafkaSchema{}
+			Expect(k8sClient.Get(ctx, namespacedName(existing), current)).Should(Succeed())
+			Expect(current.Spec).Should(Equal(existing.Spec))
+			Expect(current.Labels).ShouldNot(HaveKey(importedByLabel))
+		})
+		It("Should disambiguate resource names of subjects colliding after sanitization", func() {
+			prefix := fmt.Sprintf("legacy%d", time.Now().UnixMilli())
+			By("Given subjects differing only in characters invalid in resource names were registered")
+			srMock.RegisterSchema(prefix+"-d.value", `"string"`)
+			srMock.RegisterSchema(prefix+"-d-value", `"int"`)
+
+			By("When importing subjects")
+			anImport := anImportWithMode(v1beta1.ImportReadOnly, "^"+prefix+"-")
+			Ω(whenImportingSubjects(ctx, anImport)).ShouldNot(BeNil())
+
+			By("Then each subject should be imported as separate KafkaSchema")
+			current := &v1beta1.SchemaRegistryImport{}
+			Expect(k8sClient.Get(ctx, namespacedImportName(anImport), current)).Should(Succeed())
+			Expect(current.Status.ImportedSubjects).Should(HaveLen(2))
+			resources := map[string]string{}
+			for _, imported := range current.Status.ImportedSubjects {
+				resources[imported.Resource] = imported.Subject
+				res := &v1beta1.KafkaSchema{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: imported.Resource}, res)).
+					Should(Succeed())
+				Expect(res.Spec.SubjectName).Should(Equal(imported.Subject))
+			}
+			Expect(resources).Should(HaveLen(2))
+			Expect(resources).Should(HaveKey("imported-" + prefix + "-d-value"))
+
+			By("And importing again should keep the resources")
+			cut := &SchemaRegistryImportReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			Ω(cut.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedImportName(anImport)})).ShouldNot(BeNil())
+			Expect(k8sClient.Get(ctx, namespacedImportName(anImport), current)).Should(Succeed())
+			for _, imported := range current.Status.ImportedSubjects {
+				Expect(resources).Should(HaveKeyWithValue(imported.Resource, imported.Subject))
+			}
+		})
+		It("Should import references of the latest schema", func() {
+			prefix := fmt.Sprintf("legacy%d", time.Now().UnixMilli())
+			By("Given subject referencing schema of another subject was registered")
+			srMock.RegisterSchema("common-"+prefix, `{"type": "record", "name": "Common", "fields": []}`)
+			refs := []schemareg.SchemaReference{{Name: "Common", Subject: "common-" + prefix, Version: 1}}
+			srMock.Register(prefix+"-e-value", schemareg.RegisterSchemaReq{
+				Schema:     `{"type": "record", "name": "Event", "fields": [{"name": "common", "type": "Common"}]}`,
+				References: refs,
+			})
+
+			By("When importing subjects")
+			anImport := anImportWithMode(v1beta1.ImportReadOnly, "^"+prefix+"-")
+			Ω(whenImportingSubjects(ctx, anImport)).ShouldNot(BeNil())
+
+			By("Then references should be part of imported KafkaSchema")
+			imported := &v1beta1.KafkaSchema{}
+			Expect(k8sClient.Get(ctx,
+				types.NamespacedName{Namespace: "default", Name: "imported-" + prefix + "-e-value"},
+				imported)).Should(Succeed())
+			Expect(imported.Spec.Data.References).Should(Equal([]v1beta1.SchemaReference{
+				{Name: "Common", Subject: "common-" + prefix, Version: 1},
+			}))
+
+			By("And imported KafkaSchema should adopt the subject")
+			Ω(whenReconcilingSchema(ctx, imported)).ShouldNot(BeNil())
+			status := expectReadyConditionWithReason(ctx, imported, v1beta1.Complete)
+			Expect(status.Adoption).ShouldNot(BeNil())
+			Expect(srMock.Subjects[prefix+"-e-value"].SchemaRefs).Should(HaveLen(1))
+		})
+		It("Should import subjects of schema context resources fall back to", func() {
+			Expect(os.Setenv("DEFAULT_SCHEMA_REGISTRY_CONTEXT", ".team-a")).To(Succeed())
+			DeferCleanup(os.Unsetenv, "DEFAULT_SCHEMA_REGISTRY_CONTEXT")
+			prefix := fmt.Sprintf("legacy%d", time.Now().UnixMilli())
+			By("Given subjects with the same name were registered in default and team-a contexts")
+			srMock.RegisterSchema(prefix+"-f-value", `"int"`)
+			srMock.RegisterSchema(":.team-a:"+prefix+"-f-value", `"string"`)
+
+			By("When importing subjects")
+			anImport := anImportWithMode(v1beta1.ImportReadOnly, "^"+prefix+"-")
+			Ω(whenImportingSubjects(ctx, anImport)).ShouldNot(BeNil())
+
+			By("Then KafkaSchema should represent subject of team-a context")
+			imported := &v1beta1.KafkaSchema{}
+			Expect(k8sClient.Get(ctx,
+				types.NamespacedName{Namespace: "default", Name: "imported-" + prefix + "-f-value"},
+				imported)).Should(Succeed())
+			Expect(imported.Spec.SubjectName).Should(Equal(prefix + "-f-value"))
+			Expect(imported.Spec.SchemaRegistry.Context).Should(Equal(".team-a"))
+			Expect(imported.Spec.Data.Schema).Should(Equal(`"string"`))
+
+			By("And only subject of team-a context should be reported in status")
+			current := &v1beta1.SchemaRegistryImport{}
+			Expect(k8sClient.Get(ctx, namespacedImportName(anImport), current)).Should(Succeed())
+			Expect(current.Status.ImportedSubjects).Should(Equal([]v1beta1.ImportedSubject{
+				{Subject: ":.team-a:" + prefix + "-f-value", Resource: "imported-" + prefix + "-f-value"},
+			}))
+
+			By("And imported KafkaSchema should keep its context when default changes")
+			Expect(os.Unsetenv("DEFAULT_SCHEMA_REGISTRY_CONTEXT")).To(Succeed())
+			Ω(whenReconcilingSchema(ctx, imported)).ShouldNot(BeNil())
+			status := expectReadyConditionWithReason(ctx, imported, v1beta1.Complete)
+			Expect(status.Subject).Should(Equal(":.team-a:" + prefix + "-f-value"))
+		})
+		It("Should requeue import following requeue delay", func() {
+			By("When importing subjects with default requeue delay")
+			anImport := anImportWithMode(v1beta1.ImportReadOnly, "^nothing-")
+			result, err := whenImportingSubjects(ctx, anImport)
+
+			By("Then import should be requeued with backoff")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(result).Should(Equal(ctrl.Result{Requeue: true}))
+
+			By("And it shouldn't be requeued with negative delay")
+			cut := &SchemaRegistryImportReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), RequeueDelay: -1}
+			Expect(cut.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedImportName(anImport)})).
+				Should(Equal(ctrl.Result{RequeueAfter: -1}))
+		})
+		It("Should fail on invalid subject pattern", func() {
+			By("When importing subjects with invalid pattern")
+			anImport := anImportWithMode(v1beta1.ImportReadOnly, "(")
+			_, err := whenImportingSubjects(ctx, anImport)
+
+			By("Then reconciliation should fail")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+	Context("Resource names", func() {
+		It("Should convert rendered names to valid resource names", func() {
+			nameTemplate := template.Must(template.New("name").Parse("{{ .Subject }}"))
+			Expect(renderResourceName(nameTemplate, "com.Example_Topic-value")).
+				Should(Equal("com.example-topic-value"))
+			Expect(renderResourceName(nameTemplate, "_Foo_")).Should(Equal("foo"))
+			_, err := renderResourceName(nameTemplate, "___")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
+
+func whenImportingSubjects(ctx context.Context, anImport *v1beta1.SchemaRegistryImport) (ctrl.Result, error) {
+	lookupName := namespacedImportName(anImport)
+	cut := &SchemaRegistryImportReconciler{
+		Client: k8sClient,
+		Scheme: k8sClient.Scheme(),
+	}
+
+	By("-- creating import")
+	ExpectWithOffset(1, k8sClient.Create(ctx, anImport)).
+		To(Succeed())
+	return cut.Reconcile(ctx, reconcile.Request{NamespacedName: lookupName})
+}
+
+func namespacedImportName(resource *v1beta1.SchemaRegistryImport) types.NamespacedName {
+	return types.NamespacedName{Namespace: resource.Namespace, Name: resource.Name}
+}
+
+func anImportWithMode(mode v1beta1.ImportMode, subjectPattern string) *v1beta1.SchemaRegistryImport {
+	return &v1beta1.SchemaRegistryImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("import-%d", time.Now().UnixMilli()),
+			Namespace: "default",
+		},
+		Spec: v1beta1.SchemaRegistryImportSpec{
+			SubjectPattern:  subjectPattern,
+			TargetNamespace: "default",
+			NameTemplate:    "imported-{{ .Subject }}",
+			Mode:            mode,
+		},
+	}
+}
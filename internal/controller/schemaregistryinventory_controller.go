@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+
+	"github.com/go-logr/logr"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// SchemaRegistryInventoryReporter periodically reports subjects of all known schema registries
+// (default one and all referenced by KafkaSchema resources) in SchemaRegistryInventory resources.
+// Optionally, it garbage-collects orphaned subjects created by the operator (see OrphanGcPolicy)
+type SchemaRegistryInventoryReporter struct {
+	Interval      time.Duration
+	GcPolicy      OrphanGcPolicy
+	GcGracePeriod time.Duration
+	client.Client
+}
+
+//+kubebuilder:rbac:groups=kafka.incubly.oss,resources=schemaregistryinventories,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=kafka.incubly.oss,resources=schemaregistryinventories/status,verbs=get;update;patch
+
+// Start runs the reporter until the context is cancelled (see manager.Runnable)
+func (r *SchemaRegistryInventoryReporter) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("inventory")
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Report(ctx); err != nil {
+			logger.Error(err, "Failed to report schema registry inventory")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report updates SchemaRegistryInventory resources of all known schema registries
+func (r *SchemaRegistryInventoryReporter) Report(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("inventory")
+
+	// inventories are read before owners: subject released by finalizer of an owner listed as not being deleted
+	// yet is then released after the read, so that the report fails on conflict instead of dropping the release
+	inventories := &v1beta1.SchemaRegistryInventoryList{}
+	if err := r.List(ctx, inventories); err != nil {
+		return err
+	}
+	owners, err := r.subjectOwnersByRegistry(ctx, logger)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for registryUrl, subjectOwners := range owners {
+		if err := r.reportRegistry(ctx, registryUrl, subjectOwners, inventories.Items, logger); err != nil {
+			errs = append(errs, fmt.Errorf("failed to report inventory of %s: %w", registryUrl, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// subjectOwner is subject represented by existing KafkaSchema resource
+type subjectOwner struct {
+	v1beta1.InventorySubject
+	// deleting tells if the resource is being deleted (its finalizer may have released the subject already)
+	deleting bool
+}
+
+/*
+subjectOwnersByRegistry maps URLs of known schema registries to subjects
+represented by KafkaSchema resources (and names of those resources)
+*/
+func (r *SchemaRegistryInventoryReporter) subjectOwnersByRegistry(
+	ctx context.Context,
+	logger logr.Logger) (map[string]map[string]subjectOwner, error) {
+
+	owners := map[string]map[string]subjectOwner{}
+	if defaultClient, err := schemareg.NewClient(nil, logger); err == nil {
+		owners[defaultClient.BaseUrl.String()] = map[string]subjectOwner{}
+	}
+
+	schemas := &v1beta1.KafkaSchemaList{}
+	if err := r.List(ctx, schemas); err != nil {
+		return nil, err
+	}
+	for _, schema := range schemas.Items {
+		registryUrl := schema.Status.SchemaRegistryUrl
+		subject := schema.Status.Subject
+		if len(registryUrl) == 0 || len(subject) == 0 {
+			continue
+		}
+		if _, ok := owners[registryUrl]; !ok {
+			owners[registryUrl] = map[string]subjectOwner{}
+		}
+		if _, ok := owners[registryUrl][subject]; !ok {
+			owners[registryUrl][subject] = subjectOwner{
+				InventorySubject: v1beta1.InventorySubject{
+					Subject: subject,
+					Owner:   schema.Namespace + "/" + schema.Name,
+					Created: schema.Status.SubjectCreated,
+				},
+				deleting: !schema.GetDeletionTimestamp().IsZero(),
+			}
+		}
+	}
+	return owners, nil
+}
+
+func (r *SchemaRegistryInventoryReporter) reportRegistry(
+	ctx context.Context,
+	registryUrl string,
+	subjectOwners map[string]subjectOwner,
+	inventories []v1beta1.SchemaRegistryInventory,
+	logger logr.Logger) error {
+
+	srClient, err := schemareg.NewClient(&v1beta1.SchemaRegistry{BaseUrl: registryUrl}, logger)
+	if err != nil {
+		return err
+	}
+	// owners are known by subjects qualified with their contexts
+	allSubjects, err := srClient.ListSubjects(schemareg.AllContexts, true)
+	if err != nil {
+		return err
+	}
+	activeSubjects, err := srClient.ListSubjects(schemareg.AllContexts, false)
+	if err != nil {
+		return err
+	}
+
+	inventory, err := r.getOrCreateInventory(ctx, registryUrl, inventories)
+	if err != nil {
+		return err
+	}
+
+	// subjects that were managed at the time of previous report
+	previousOwners := map[string]v1beta1.InventorySubject{}
+	for _, managed := range inventory.Status.ManagedSubjects {
+		previousOwners[managed.Subject] = managed
+	}
+	for _, orphaned := range inventory.Status.OrphanedSubjects {
+		previousOwners[orphaned.Subject] = orphaned
+	}
+
+	inventory.Status = buildInventory(allSubjects, activeSubjects, subjectOwners, previousOwners)
+	r.collectOrphans(&inventory.Status, srClient, logger)
+	inventory.Status.SchemaRegistryUrl = registryUrl
+	inventory.Status.LastUpdateTsEpoch = time.Now().UnixMilli()
+	return r.Status().Update(ctx, inventory)
+}
+
+/*
+buildInventory classifies subjects of schema registry. Subject whose owner is gone becomes orphaned,
+unless the owner released it on deletion. Subjects left behind by resources deleted before they were first
+reported (e.g. before the inventory was enabled) are unknown to the inventory, so they're reported as unmanaged
+(and never garbage-collected)
+*/
+func buildInventory(
+	allSubjects []string,
+	activeSubjects []string,
+	subjectOwners map[string]subjectOwner,
+	previousOwners map[string]v1beta1.InventorySubject) v1beta1.SchemaRegistryInventoryStatus {
+
+	status := v1beta1.SchemaRegistryInventoryStatus{}
+	active := map[string]bool{}
+	for _, subject := range activeSubjects {
+		active[subject] = true
+		if owner, ok := subjectOwners[subject]; ok {
+			if previous, ok := previousOwners[subject]; ok && owner.deleting && previous.Owner == owner.Owner {
+				// keep release recorded by finalizer of the resource about to be gone
+				owner.Released = previous.Released
+			}
+			status.ManagedSubjects = append(status.ManagedSubjects, owner.InventorySubject)
+		} else if owner, ok := previousOwners[subject]; ok && !owner.Released {
+			if owner.OrphanedSince == nil {
+				now := metav1.Now()
+				owner.OrphanedSince = &now
+			}
+			status.OrphanedSubjects = append(status.OrphanedSubjects, owner)
+		} else {
+			status.UnmanagedSubjects = append(status.UnmanagedSubjects, subject)
+		}
+	}
+	for _, subject := range allSubjects {
+		if !active[subject] {
+			status.SoftDeletedSubjects = append(status.SoftDeletedSubjects, subject)
+		}
+	}
+	sort.Strings(status.UnmanagedSubjects)
+	sort.Strings(status.SoftDeletedSubjects)
+	return status
+}
+
+/*
+releaseInventorySubject marks subject of the resource released in inventory of its schema registry,
+so that it isn't reported as orphaned (nor garbage-collected) once the resource is gone.
+Subjects the inventory doesn't know yet (or missing inventory) are left as they are
+*/
+func releaseInventorySubject(ctx context.Context, c client.Client, res *v1beta1.KafkaSchema) error {
+	if len(res.Status.SchemaRegistryUrl) == 0 || len(res.Status.Subject) == 0 {
+		return nil
+	}
+	name, err := inventoryName(res.Status.SchemaRegistryUrl)
+	if err != nil {
+		return err
+	}
+	inventory := &v1beta1.SchemaRegistryInventory{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, inventory); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	owner := res.Namespace + "/" + res.Name
+	for i := range inventory.Status.ManagedSubjects {
+		managed := &inventory.Status.ManagedSubjects[i]
+		if managed.Subject != res.Status.Subject || managed.Owner != owner || managed.Released {
+			continue
+		}
+		managed.Released = true
+		return c.Status().Update(ctx, inventory)
+	}
+	return nil
+}
+
+// getOrCreateInventory returns inventory of schema registry from previously read ones, creating missing one
+func (r *SchemaRegistryInventoryReporter) getOrCreateInventory(
+	ctx context.Context,
+	registryUrl string,
+	inventories []v1beta1.SchemaRegistryInventory) (*v1beta1.SchemaRegistryInventory, error) {
+
+	name, err := inventoryName(registryUrl)
+	if err != nil {
+		return nil, err
+	}
+	for i := range inventories {
+		if inventories[i].Name == name {
+			return &inventories[i], nil
+		}
+	}
+	inventory := &v1beta1.SchemaRegistryInventory{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	if err := r.Create(ctx, inventory); err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// inventoryName derives name of SchemaRegistryInventory resource from schema registry URL
+func inventoryName(registryUrl string) (string, error) {
+	parsedUrl, err := url.Parse(registryUrl)
+	if err != nil {
+		return "", err
+	}
+	return notBlank(sanitizeResourceName(parsedUrl.Host+parsedUrl.Path), "inventory name of "+registryUrl)
+}
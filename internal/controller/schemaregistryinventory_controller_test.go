@@ -0,0 +1,298 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("SchemaRegistryInventory Reporter", func() {
+
+	BeforeEach(func() {
+		srMock.Clear()
+	})
+
+	ctx := context.Background()
+	cut := func() *SchemaRegistryInventoryReporter {
+		return &SchemaRegistryInventoryReporter{Client: k8sClient}
+	}
+
+	Context("Reporting inventory", func() {
+		It("Should report managed, unmanaged and soft-deleted subjects", func() {
+			suffix := fmt.Sprintf("%d", time.Now().UnixMilli())
+			By("Given subject managed by KafkaSchema")
+			managed := aSchemaWithCleanupPolicy(v1beta1.DISABLED)
+			managed.Spec.SubjectName = "managed-" + suffix
+			Ω(whenCreatingSchema(ctx, managed)).ShouldNot(BeNil())
+			By("And subjects registered directly in registry")
+			srMock.RegisterSchema("unmanaged-"+suffix, `"string"`)
+			srMock.RegisterSchema("deleted-"+suffix, `"string"`)
+			Ω(srMock.DeleteSubject("deleted-"+suffix, false)).ShouldNot(BeNil())
+
+			By("When reporting inventory")
+			Expect(cut().Report(ctx)).Should(Succeed())
+
+			By("Then inventory should reflect subjects")
+			inventory := expectInventory(ctx)
+			Expect(inventory.Status.SchemaRegistryUrl).Should(Equal(srMockServer.URL()))
+			Expect(inventory.Status.ManagedSubjects).Should(ContainElement(v1beta1.InventorySubject{
+				Subject: "managed-" + suffix,
+				Owner:   managed.Namespace + "/" + managed.Name,
+				Created: true,
+			}))
+			Expect(inventory.Status.UnmanagedSubjects).Should(ContainElement("unmanaged-" + suffix))
+			Expect(inventory.Status.SoftDeletedSubjects).Should(ContainElement("deleted-" + suffix))
+			Expect(inventory.Status.OrphanedSubjects).Should(BeEmpty())
+		})
+		It("Should report subjects of non-default schema contexts", func() {
+			suffix := fmt.Sprintf("%d", time.Now().UnixMilli())
+			By("Given subject managed by KafkaSchema in team-a context")
+			managed := aSchemaWithCleanupPolicy(v1beta1.DISABLED)
+			managed.Spec.SubjectName = "managed-" + suffix
+			managed.Spec.SchemaRegistry.Context = ".team-a"
+			Ω(whenCreatingSchema(ctx, managed)).ShouldNot(BeNil())
+			By("And subject registered directly in team-a context")
+			srMock.RegisterSchema(":.team-a:unmanaged-"+suffix, `"string"`)
+
+			By("When reporting inventory")
+			Expect(cut().Report(ctx)).Should(Succeed())
+
+			By("Then subjects should be reported qualified with their context")
+			inventory := expectInventory(ctx)
+			Expect(inventory.Status.ManagedSubjects).Should(ContainElement(v1beta1.InventorySubject{
+				Subject: ":.team-a:managed-" + suffix,
+				Owner:   managed.Namespace + "/" + managed.Name,
+				Created: true,
+			}))
+			Expect(inventory.Status.UnmanagedSubjects).Should(ContainElement(":.team-a:unmanaged-" + suffix))
+			Expect(inventory.Status.OrphanedSubjects).Should(BeEmpty())
+		})
+		It("Should report subjects whose KafkaSchema no longer exists", func() {
+			suffix := fmt.Sprintf("%d", time.Now().UnixMilli())
+			By("Given subject managed by KafkaSchema was reported")
+			managed := aSchemaWithCleanupPolicy(v1beta1.DISABLED)
+			managed.Spec.SubjectName = "orphaned-" + suffix
+			Ω(whenCreatingSchema(ctx, managed)).ShouldNot(BeNil())
+			Expect(cut().Report(ctx)).Should(Succeed())
+
+			By("And KafkaSchema was deleted without its finalizer")
+			whenDeletingSchemaWithoutFinalizer(ctx, managed)
+
+			By("When reporting inventory again")
+			Expect(cut().Report(ctx)).Should(Succeed())
+
+			By("Then subject should be reported as orphaned")
+			inventory := expectInventory(ctx)
+			orphaned := expectInventorySubject(inventory.Status.OrphanedSubjects, "orphaned-"+suffix)
+			Expect(orphaned.Owner).Should(Equal(managed.Namespace + "/" + managed.Name))
+			Expect(orphaned.OrphanedSince).ShouldNot(BeNil())
+			Expect(inventory.Status.UnmanagedSubjects).ShouldNot(ContainElement("orphaned-" + suffix))
+		})
+		It("Should report subject released by deleted KafkaSchema as unmanaged", func() {
+			suffix := fmt.Sprintf("%d", time.Now().UnixMilli())
+			By("Given subject managed by KafkaSchema with DISABLED cleanup policy was reported")
+			managed := aSchemaWithCleanupPolicy(v1beta1.DISABLED)
+			managed.Spec.SubjectName = "released-" + suffix
+			Ω(whenCreatingSchema(ctx, managed)).ShouldNot(BeNil())
+			Expect(cut().Report(ctx)).Should(Succeed())
+
+			By("When KafkaSchema is deleted (leaving the subject in registry)")
+			Ω(whenDeletingExistingSchema(ctx, managed)).ShouldNot(BeNil())
+
+			By("Then subject should be released in inventory")
+			released := expectInventorySubject(expectInventory(ctx).Status.ManagedSubjects, "released-"+suffix)
+			Expect(released.Released).Should(BeTrue())
+
+			By("And reported as unmanaged once KafkaSchema is gone")
+			Expect(withGc(cut(), GcHard, 0).Report(ctx)).Should(Succeed())
+			inventory := expectInventory(ctx)
+			Expect(inventory.Status.UnmanagedSubjects).Should(ContainElement("released-" + suffix))
+			Expect(inventory.Status.OrphanedSubjects).Should(BeEmpty())
+
+			By("And it should never be garbage-collected")
+			Expect(srMock.Subjects).Should(HaveKey("released-" + suffix))
+		})
+		It("Should not drop release of subject recorded while reporting", func() {
+			suffix := fmt.Sprintf("%d", time.Now().UnixMilli())
+			By("Given subject managed by KafkaSchema was reported")
+			managed := aSchemaWithCleanupPolicy(v1beta1.DISABLED)
+			managed.Spec.SubjectName = "racing-" + suffix
+			Ω(whenCreatingSchema(ctx, managed)).ShouldNot(BeNil())
+			Expect(cut().Report(ctx)).Should(Succeed())
+
+			By("When finalizer releases the subject right after owners were listed")
+			Expect(k8sClient.Get(ctx, namespacedName(managed), managed)).Should(Succeed())
+			racing := &SchemaRegistryInventoryReporter{Client: listingHook{Client: k8sClient, afterList: func() {
+				Expect(releaseInventorySubject(ctx, k8sClient, managed)).Should(Succeed())
+			}}}
+			err := racing.Report(ctx)
+
+			By("Then report should fail on conflict")
+			Expect(apierrors.IsConflict(err)).Should(BeTrue())
+
+			By("And release should be kept")
+			released := expectInventorySubject(expectInventory(ctx).Status.ManagedSubjects, "racing-"+suffix)
+			Expect(released.Released).Should(BeTrue())
+		})
+		It("Should keep release of subject whose KafkaSchema is being deleted", func() {
+			owner := v1beta1.InventorySubject{Subject: "s", Owner: "default/s", Created: true}
+			released := owner
+			released.Released = true
+
+			By("When KafkaSchema being deleted is reported after its finalizer released the subject")
+			status := buildInventory([]string{"s"}, []string{"s"},
+				map[string]subjectOwner{"s": {InventorySubject: owner, deleting: true}},
+				map[string]v1beta1.InventorySubject{"s": released})
+
+			By("Then release should be kept")
+			Expect(status.ManagedSubjects).Should(Equal([]v1beta1.InventorySubject{released}))
+
+			By("And it should be dropped for KafkaSchema which isn't being deleted")
+			status = buildInventory([]string{"s"}, []string{"s"},
+				map[string]subjectOwner{"s": {InventorySubject: owner}},
+				map[string]v1beta1.InventorySubject{"s": released})
+			Expect(status.ManagedSubjects).Should(Equal([]v1beta1.InventorySubject{owner}))
+		})
+	})
+	Context("Garbage collection of orphaned subjects", func() {
+		It("Should soft-delete orphaned subject created by the operator", func() {
+			subject := givenOrphanedSubject(ctx, cut(), false)
+
+			By("When reporting inventory with SOFT garbage collection")
+			Expect(withGc(cut(), GcSoft, 0).Report(ctx)).Should(Succeed())
+
+			By("Then subject should be soft-deleted")
+			Expect(srMock.Subjects).ShouldNot(HaveKey(subject))
+			Expect(srMock.SoftDeletedSubjects).Should(HaveKey(subject))
+			inventory := expectInventory(ctx)
+			Expect(inventory.Status.OrphanedSubjects).Should(BeEmpty())
+			Expect(inventory.Status.SoftDeletedSubjects).Should(ContainElement(subject))
+		})
+		It("Should hard-delete orphaned subject created by the operator", func() {
+			subject := givenOrphanedSubject(ctx, cut(), false)
+
+			By("When reporting inventory with HARD garbage collection")
+			Expect(withGc(cut(), GcHard, 0).Report(ctx)).Should(Succeed())
+
+			By("Then subject should be hard-deleted")
+			Expect(srMock.HardDeletedSubjects).Should(HaveKey(subject))
+			Expect(expectInventory(ctx).Status.SoftDeletedSubjects).ShouldNot(ContainElement(subject))
+		})
+		It("Should only mark orphaned subject as collectable with REPORT policy", func() {
+			subject := givenOrphanedSubject(ctx, cut(), false)
+
+			By("When reporting inventory with REPORT garbage collection")
+			Expect(withGc(cut(), GcReport, 0).Report(ctx)).Should(Succeed())
+
+			By("Then subject should be left untouched, but marked as collectable")
+			Expect(srMock.Subjects).Should(HaveKey(subject))
+			orphaned := expectInventorySubject(expectInventory(ctx).Status.OrphanedSubjects, subject)
+			Expect(orphaned.Collectable).Should(BeTrue())
+		})
+		It("Should not collect orphaned subject before grace period", func() {
+			subject := givenOrphanedSubject(ctx, cut(), false)
+
+			By("When reporting inventory with SOFT garbage collection and long grace period")
+			Expect(withGc(cut(), GcSoft, time.Hour).Report(ctx)).Should(Succeed())
+
+			By("Then subject should be left untouched")
+			Expect(srMock.Subjects).Should(HaveKey(subject))
+			orphaned := expectInventorySubject(expectInventory(ctx).Status.OrphanedSubjects, subject)
+			Expect(orphaned.Collectable).Should(BeFalse())
+		})
+		It("Should not collect orphaned subject not created by the operator", func() {
+			subject := givenOrphanedSubject(ctx, cut(), true)
+
+			By("When reporting inventory with HARD garbage collection")
+			Expect(withGc(cut(), GcHard, 0).Report(ctx)).Should(Succeed())
+
+			By("Then adopted subject should be left untouched")
+			Expect(srMock.Subjects).Should(HaveKey(subject))
+			orphaned := expectInventorySubject(expectInventory(ctx).Status.OrphanedSubjects, subject)
+			Expect(orphaned.Created).Should(BeFalse())
+			Expect(orphaned.Collectable).Should(BeFalse())
+		})
+	})
+	Context("Inventory names", func() {
+		It("Should derive inventory name from registry URL", func() {
+			Expect(inventoryName("https://My-Registry:8081/api/")).Should(Equal("my-registry-8081-api"))
+		})
+	})
+})
+
+/*
+givenOrphanedSubject registers subject via KafkaSchema (or adopts pre-existing one),
+reports it as managed and deletes the resource without its finalizer (e.g. removed manually)
+*/
+func givenOrphanedSubject(ctx context.Context, reporter *SchemaRegistryInventoryReporter, adopted bool) string {
+	subject := fmt.Sprintf("gc-%d", time.Now().UnixMilli())
+	if adopted {
+		srMock.RegisterSchema(subject, `"string"`)
+	}
+	res := aSchemaWithCleanupPolicy(v1beta1.DISABLED)
+	res.Spec.SubjectName = subject
+	_, err := whenCreatingSchema(ctx, res)
+	ExpectWithOffset(1, err).ShouldNot(HaveOccurred())
+	ExpectWithOffset(1, reporter.Report(ctx)).Should(Succeed())
+	whenDeletingSchemaWithoutFinalizer(ctx, res)
+	return subject
+}
+
+func whenDeletingSchemaWithoutFinalizer(ctx context.Context, res *v1beta1.KafkaSchema) {
+	By("-- deleting schema without its finalizer")
+	ExpectWithOffset(2, k8sClient.Get(ctx, namespacedName(res), res)).Should(Succeed())
+	res.Finalizers = nil
+	ExpectWithOffset(2, k8sClient.Update(ctx, res)).Should(Succeed())
+	ExpectWithOffset(2, k8sClient.Delete(ctx, res)).Should(Succeed())
+}
+
+// listingHook runs afterList once KafkaSchema resources are listed
+type listingHook struct {
+	client.Client
+	afterList func()
+}
+
+func (c listingHook) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	err := c.Client.List(ctx, list, opts...)
+	if _, ok := list.(*v1beta1.KafkaSchemaList); ok && err == nil {
+		c.afterList()
+	}
+	return err
+}
+
+func withGc(
+	reporter *SchemaRegistryInventoryReporter,
+	policy OrphanGcPolicy,
+	gracePeriod time.Duration) *SchemaRegistryInventoryReporter {
+
+	reporter.GcPolicy = policy
+	reporter.GcGracePeriod = gracePeriod
+	return reporter
+}
+
+func expectInventorySubject(subjects []v1beta1.InventorySubject, subject string) v1beta1.InventorySubject {
+	for _, inventorySubject := range subjects {
+		if inventorySubject.Subject == subject {
+			return inventorySubject
+		}
+	}
+	Fail("subject " + subject + " not found in inventory")
+	return v1beta1.InventorySubject{}
+}
+
+func expectInventory(ctx context.Context) *v1beta1.SchemaRegistryInventory {
+	name, err := inventoryName(srMockServer.URL())
+	ExpectWithOffset(1, err).Should(Succeed())
+	inventory := &v1beta1.SchemaRegistryInventory{}
+	ExpectWithOffset(1, k8sClient.Get(ctx, types.NamespacedName{Name: name}, inventory)).
+		Should(Succeed())
+	return inventory
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"reflect"
+	"strings"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// desiredSubjectConfig returns subject level config defined by the resource
+func desiredSubjectConfig(res *v1beta1.KafkaSchema) schemareg.SubjectConfigReq {
+	desired := schemareg.SubjectConfigReq{
+		Compatibility: res.Spec.Data.Compatibility,
+	}
+	if res.Spec.SubjectConfig != nil {
+		desired.SubjectConfig = *res.Spec.SubjectConfig
+	}
+	return desired
+}
+
+/*
+diffSubjectConfig returns names of subject level config settings which differ from the desired ones,
+and tells if any of them has to be removed (i.e. it's set in registry, but not in the resource)
+*/
+func diffSubjectConfig(desired schemareg.SubjectConfigReq, actual *schemareg.SubjectConfigRes) ([]string, bool) {
+	if actual == nil {
+		actual = &schemareg.SubjectConfigRes{}
+	}
+	var diff []string
+	removed := false
+	if desired.Compatibility != actual.CompatibilityLevel {
+		diff = append(diff, "compatibility")
+		removed = len(desired.Compatibility) == 0
+	}
+	desiredValue := reflect.ValueOf(desired.SubjectConfig)
+	actualValue := reflect.ValueOf(actual.SubjectConfig)
+	for i := 0; i < desiredValue.NumField(); i++ {
+		if equality.Semantic.DeepEqual(desiredValue.Field(i).Interface(), actualValue.Field(i).Interface()) {
+			continue
+		}
+		jsonTag := desiredValue.Type().Field(i).Tag.Get("json")
+		diff = append(diff, strings.Split(jsonTag, ",")[0])
+		removed = removed || desiredValue.Field(i).IsZero()
+	}
+	return diff, removed
+}
+
+/*
+reconcileSubjectConfig converges subject level config to the resource.
+Since schema registry leaves settings missing in update request untouched,
+subject level config is removed first if any setting isn't defined by the resource anymore
+(so subject falls back to global config)
+*/
+func reconcileSubjectConfig(
+	subjectName string,
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient) error {
+
+	desired := desiredSubjectConfig(res)
+	actual, err := srClient.GetSubjectConfig(subjectName)
+	if err != nil {
+		return err
+	}
+	diff, removed := diffSubjectConfig(desired, actual)
+	if len(diff) == 0 {
+		return nil
+	}
+	if removed {
+		if err := srClient.DeleteSubjectConfig(subjectName); err != nil {
+			return err
+		}
+	}
+	if reflect.ValueOf(desired).IsZero() {
+		return nil
+	}
+	return srClient.SetSubjectConfig(subjectName, desired)
+}
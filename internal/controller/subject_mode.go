@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"incubly.oss/kafka-schema-operator/api/v1beta1"
+	"incubly.oss/kafka-schema-operator/internal/schemareg"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+/*
+reconcileSubjectMode converges subject level mode to the resource (if it defines one).
+Subject mode isn't touched if the resource doesn't define it, so the mode set outside the operator
+(e.g. read-only freeze of the subject) is left in place.
+Switching to IMPORT mode is forced, since subjects managed by the resource are usually non-empty
+*/
+func reconcileSubjectMode(
+	subjectName string,
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient) error {
+
+	desired := res.Spec.SubjectMode
+	if len(desired) == 0 {
+		return nil
+	}
+	actual, err := srClient.GetSubjectMode(subjectName)
+	if err != nil || actual == desired {
+		return err
+	}
+	return srClient.SetSubjectMode(subjectName, desired, desired == v1beta1.ModeImport)
+}
+
+func isReadOnlyMode(mode v1beta1.SubjectMode) bool {
+	return mode == v1beta1.ModeReadOnly || mode == v1beta1.ModeReadOnlyOverride
+}
+
+/*
+registerUnlessReadOnly registers the schema, unless the subject is read-only
+(due to its subject level mode or global mode of schema registry).
+Schema of read-only subject is left as it is if it was registered already,
+otherwise registration is reported as blocked
+*/
+func registerUnlessReadOnly(
+	subjectName string,
+	res *v1beta1.KafkaSchema,
+	srClient *schemareg.SrClient,
+	registerReq schemareg.RegisterSchemaReq,
+	previouslyRegistered *schemareg.SubjectSchema) (int, bool, error) {
+
+	if !isReadOnlyMode(res.Spec.SubjectMode) {
+		var schemaId int
+		var err error
+		if isPinned(res) {
+			schemaId, err = registerPinned(subjectName, res, srClient, registerReq, previouslyRegistered)
+		} else {
+			schemaId, err = srClient.RegisterSchema(subjectName, registerReq)
+		}
+		if !errors.As(err, &schemareg.OperationNotPermitted{}) {
+			return schemaId, false, err
+		}
+	}
+	if previouslyRegistered != nil {
+		return previouslyRegistered.Id, false, verifyPins(res.Spec.Data, previouslyRegistered)
+	}
+	return 0, true, nil
+}
+
+// registrationBlocked reports schema which can't be registered, since the subject is read-only
+func (r *KafkaSchemaReconciler) registrationBlocked(
+	ctx context.Context,
+	res *v1beta1.KafkaSchema,
+	logger logr.Logger) (ctrl.Result, error) {
+
+	msg := fmt.Sprintf("Subject %s is read-only, schema registration skipped", res.Status.Subject)
+	logger.Info(msg)
+	res.SetCondition("ReadOnly", v1beta1.RegistrationBlocked, msg)
+	res.SetReadyReason(v1beta1.ReadOnlySubject, msg)
+	res.Status.Healthy = false
+	res.Status.Status = "False"
+	if err := r.Status().Update(ctx, res); err != nil {
+		logger.Error(err, "failed to update resource status")
+		return ctrl.Result{}, err
+	}
+	return r.requeue(), nil
+}
@@ -45,8 +45,12 @@ func extractRecordName(data v1beta1.KafkaSchemaData) (string, error) {
 	if data.Format != v1beta1.AVRO {
 		return "", fmt.Errorf("record name strategy is only supported for AVRO schemas")
 	}
+	schema := []byte(data.Schema)
+	if data.SchemaObject != nil {
+		schema = data.SchemaObject.Raw
+	}
 	avroSchema := AvroSchema{}
-	err := json.Unmarshal([]byte(data.Schema), &avroSchema)
+	err := json.Unmarshal(schema, &avroSchema)
 	if err != nil {
 		return "", err
 	}
@@ -5,7 +5,11 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"incubly.oss/kafka-schema-operator/api/v1beta1"
@@ -22,31 +26,95 @@ type SchemaRef struct {
 	schemaId int
 }
 
+// SchemaId returns id of the schema registered under the version
+func (r SchemaRef) SchemaId() int {
+	return r.schemaId
+}
+
 type Subject struct {
 	CompatibilityMode v1beta1.CompatibilityMode
-	SchemaRefs        []SchemaRef
+	// Config is subject level config (besides CompatibilityMode)
+	Config     v1beta1.SubjectConfig
+	SchemaRefs []SchemaRef
+	// SoftDeletedRefs are versions soft-deleted individually (see DeleteSubjectVersion)
+	SoftDeletedRefs []SchemaRef
 }
 
-func (s *Subject) setSchemaAsCurrentVersion(schemaId int) {
-	if len(s.SchemaRefs) == 0 {
-		s.SchemaRefs = append(s.SchemaRefs, SchemaRef{
-			version:  0,
-			schemaId: schemaId,
-		})
-	} else {
-		currentRef := s.SchemaRefs[len(s.SchemaRefs)-1]
-		if currentRef.schemaId != schemaId {
-			s.SchemaRefs = append(s.SchemaRefs, SchemaRef{
-				version:  currentRef.version + 1,
-				schemaId: schemaId,
-			})
+func (s *Subject) hasConfig() bool {
+	return len(s.CompatibilityMode) > 0 || !reflect.ValueOf(s.Config).IsZero()
+}
+
+func (s *Subject) config() schemareg.SubjectConfigRes {
+	return schemareg.SubjectConfigRes{
+		CompatibilityLevel: s.CompatibilityMode,
+		SubjectConfig:      s.Config,
+	}
+}
+
+func (s *Subject) setSchemaAsCurrentVersion(schemaId int, version int) {
+	for _, ref := range s.SchemaRefs {
+		// schema registry doesn't create new version if schema is already registered under the subject
+		if ref.schemaId == schemaId {
+			return
 		}
 	}
+	if version == 0 {
+		version = s.lastVersion() + 1
+	}
+	s.SchemaRefs = append(s.SchemaRefs, SchemaRef{
+		version:  version,
+		schemaId: schemaId,
+	})
+}
+
+// lastVersion returns the highest version of the subject, including soft-deleted ones (never reused)
+func (s *Subject) lastVersion() int {
+	lastVersion := 0
+	for _, refs := range [][]SchemaRef{s.SchemaRefs, s.SoftDeletedRefs} {
+		for _, ref := range refs {
+			lastVersion = max(lastVersion, ref.version)
+		}
+	}
+	return lastVersion
+}
+
+// Versions returns active (not deleted) versions of the subject
+func (s *Subject) Versions() []int {
+	return schemaVersions(s)
+}
+
+// SoftDeletedVersions returns individually soft-deleted versions of the subject
+func (s *Subject) SoftDeletedVersions() []int {
+	versions := make([]int, len(s.SoftDeletedRefs))
+	for i, ref := range s.SoftDeletedRefs {
+		versions[i] = ref.version
+	}
+	return versions
+}
+
+// LatestSchemaId returns id of the schema registered as the latest version of the subject
+func (s *Subject) LatestSchemaId() int {
+	if len(s.SchemaRefs) == 0 {
+		return 0
+	}
+	return s.SchemaRefs[len(s.SchemaRefs)-1].schemaId
 }
 
 type SchemaRegMock struct {
-	Subjects            map[string]*Subject
-	Schemas             map[int]string
+	Subjects map[string]*Subject
+	Schemas  map[int]string
+	// Metadata maps ids of schemas registered with metadata to their metadata
+	Metadata map[int]*v1beta1.Metadata
+	// RuleSets maps ids of schemas registered with rule sets to their rule sets
+	RuleSets map[int]*v1beta1.RuleSet
+	// SchemaReferences maps ids of schemas registered with references to their references
+	SchemaReferences map[int][]schemareg.SchemaReference
+	// Modes maps subjects to their subject level modes
+	Modes map[string]v1beta1.SubjectMode
+	// GlobalMode applies to subjects without subject level mode (READWRITE if empty)
+	GlobalMode v1beta1.SubjectMode
+	// References maps referenced subject versions to ids of referencing schemas
+	References          map[schemareg.SubjectVersion][]int
 	SoftDeletedSubjects map[string]*Subject
 	HardDeletedSubjects map[string]*Subject
 	logger              logr.Logger
@@ -58,6 +126,11 @@ func NewSchemaRegMock(logger logr.Logger) *SchemaRegMock {
 	return &SchemaRegMock{
 		Subjects:            map[string]*Subject{},
 		Schemas:             map[int]string{},
+		Metadata:            map[int]*v1beta1.Metadata{},
+		RuleSets:            map[int]*v1beta1.RuleSet{},
+		Modes:               map[string]v1beta1.SubjectMode{},
+		SchemaReferences:    map[int][]schemareg.SchemaReference{},
+		References:          map[schemareg.SubjectVersion][]int{},
 		SoftDeletedSubjects: map[string]*Subject{},
 		HardDeletedSubjects: map[string]*Subject{},
 		logger:              logger,
@@ -67,22 +140,97 @@ func NewSchemaRegMock(logger logr.Logger) *SchemaRegMock {
 
 func (m *SchemaRegMock) GetServer() *ghttp.Server {
 	server := ghttp.NewServer()
+	// subjects may contain escaped "/" (e.g. imported Protobuf files), so routes are matched against escaped paths
+	server.HTTPTestServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		req.URL.Path = req.URL.EscapedPath()
+		server.ServeHTTP(w, req)
+	})
 
 	server.RouteToHandler(
 		"POST",
-		regexp.MustCompile(`^/subjects/[a-zA-Z0-9-_.]+/versions$`),
+		regexp.MustCompile(`^/subjects/[a-zA-Z0-9-_.:%]+/versions$`),
 		m.registerSubjectHandler(),
 	)
+	server.RouteToHandler(
+		"POST",
+		regexp.MustCompile(`^/subjects/[a-zA-Z0-9-_.:%]+$`),
+		m.lookupSchemaHandler(),
+	)
+	server.RouteToHandler(
+		"GET",
+		regexp.MustCompile(`^/subjects/[a-zA-Z0-9-_.:%]+/versions/latest$`),
+		m.getLatestSchemaHandler(),
+	)
+	server.RouteToHandler(
+		"GET",
+		regexp.MustCompile(`^/subjects/[a-zA-Z0-9-_.:%]+/versions$`),
+		m.listVersionsHandler(),
+	)
+	server.RouteToHandler(
+		"GET",
+		regexp.MustCompile(`^/subjects/[a-zA-Z0-9-_.:%]+/versions/[0-9]+$`),
+		m.getSchemaVersionHandler(),
+	)
+	server.RouteToHandler(
+		"GET",
+		regexp.MustCompile(`^/subjects/[a-zA-Z0-9-_.:%]+/versions/[0-9]+/referencedby$`),
+		m.getReferencedByHandler(),
+	)
+	server.RouteToHandler(
+		"GET",
+		regexp.MustCompile(`^/schemas/ids/[0-9]+$`),
+		m.getSchemaByIdHandler(),
+	)
+	server.RouteToHandler(
+		"GET",
+		regexp.MustCompile(`^/schemas/ids/[0-9]+/versions$`),
+		m.getSchemaVersionsHandler(),
+	)
+	server.RouteToHandler(
+		"GET",
+		regexp.MustCompile(`^/subjects$`),
+		m.listSubjectsHandler(),
+	)
 	server.RouteToHandler(
 		"DELETE",
-		regexp.MustCompile(`^/subjects/[a-zA-Z0-9-_.]+$`),
+		regexp.MustCompile(`^/subjects/[a-zA-Z0-9-_.:%]+$`),
 		m.deleteSubjectHandler(),
 	)
+	server.RouteToHandler(
+		"DELETE",
+		regexp.MustCompile(`^/subjects/[a-zA-Z0-9-_.:%]+/versions/[0-9]+$`),
+		m.deleteSubjectVersionHandler(),
+	)
 	server.RouteToHandler(
 		"PUT",
-		regexp.MustCompile(`^/config/[a-zA-Z0-9-_.]+$`),
+		regexp.MustCompile(`^/config/[a-zA-Z0-9-_.:%]+$`),
 		m.setCompatibilityModeHandler(),
 	)
+	server.RouteToHandler(
+		"GET",
+		regexp.MustCompile(`^/config/[a-zA-Z0-9-_.:%]+$`),
+		m.getCompatibilityModeHandler(),
+	)
+	server.RouteToHandler(
+		"DELETE",
+		regexp.MustCompile(`^/config/[a-zA-Z0-9-_.:%]+$`),
+		m.deleteSubjectConfigHandler(),
+	)
+	server.RouteToHandler(
+		"GET",
+		regexp.MustCompile(`^/mode/[a-zA-Z0-9-_.:%]+$`),
+		m.getSubjectModeHandler(),
+	)
+	server.RouteToHandler(
+		"PUT",
+		regexp.MustCompile(`^/mode/[a-zA-Z0-9-_.:%]+$`),
+		m.setSubjectModeHandler(),
+	)
+	server.RouteToHandler(
+		"DELETE",
+		regexp.MustCompile(`^/mode/[a-zA-Z0-9-_.:%]+$`),
+		m.deleteSubjectModeHandler(),
+	)
 
 	return server
 }
@@ -93,12 +241,37 @@ func (m *SchemaRegMock) registerSubjectHandler() http.HandlerFunc {
 			return
 		}
 
-		subjectName := strings.Split(req.URL.Path, "/")[2]
+		subjectName := pathSubject(req)
 
 		m.logger.Info("Schema-reg: registering subject " + subjectName)
 
 		registerSchemaReq := readJsonBody(req, &schemareg.RegisterSchemaReq{})
 
+		if mode := m.subjectMode(subjectName); mode == v1beta1.ModeReadOnly || mode == v1beta1.ModeReadOnlyOverride {
+			writeOperationNotPermitted(w, fmt.Sprintf("Subject %s is in read-only mode", subjectName))
+			return
+		}
+		if (registerSchemaReq.Id > 0 || registerSchemaReq.Version > 0) && m.subjectMode(subjectName) != v1beta1.ModeImport {
+			writeOperationNotPermitted(w, fmt.Sprintf("Subject %s is not in import mode", subjectName))
+			return
+		}
+		if existing, ok := m.Schemas[registerSchemaReq.Id]; ok && existing != registerSchemaReq.Schema {
+			w.WriteHeader(422)
+			_, _ = w.Write([]byte(fmt.Sprintf(
+				`{"error_code":42207,"message":"Overwrite new schema with id %d is not permitted."}`,
+				registerSchemaReq.Id)))
+			return
+		}
+
+		for _, ref := range registerSchemaReq.References {
+			if m.findVersion(ref.Subject, ref.Version) == nil {
+				w.WriteHeader(422)
+				_, _ = w.Write([]byte(fmt.Sprintf(
+					`{"error_code":42201,"message":"Invalid schema: reference %s not found"}`, ref.Name)))
+				return
+			}
+		}
+
 		schema, parseSchemaErr := parseSchema(*registerSchemaReq)
 		if parseSchemaErr != nil {
 			w.WriteHeader(422)
@@ -106,19 +279,272 @@ func (m *SchemaRegMock) registerSubjectHandler() http.HandlerFunc {
 			return
 		}
 
+		registerSchemaReq.Schema = schema
+		schemaId := m.Register(subjectName, *registerSchemaReq)
+
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"id": %d}`, schemaId)))
+		w.WriteHeader(200)
+	}
+}
+
+func (m *SchemaRegMock) lookupSchemaHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(LookupSchema, w) {
+			return
+		}
+		subjectName := pathSubject(req)
+		lookupReq := readJsonBody(req, &schemareg.RegisterSchemaReq{})
+
+		subject, ok := m.Subjects[subjectName]
+		if !ok {
+			writeSubjectNotFound(w, subjectName)
+			return
+		}
+		for _, ref := range subject.SchemaRefs {
+			if m.Schemas[ref.schemaId] == lookupReq.Schema &&
+				(lookupReq.Metadata == nil || reflect.DeepEqual(m.Metadata[ref.schemaId], lookupReq.Metadata)) &&
+				(lookupReq.RuleSet == nil || reflect.DeepEqual(m.RuleSets[ref.schemaId], lookupReq.RuleSet)) &&
+				(lookupReq.References == nil || reflect.DeepEqual(m.SchemaReferences[ref.schemaId], lookupReq.References)) {
+				writeJson(w, m.toSubjectSchema(subjectName, ref))
+				return
+			}
+		}
+		w.WriteHeader(404)
+		_, _ = w.Write([]byte(`{"error_code":40403,"message":"Schema not found"}`))
+	}
+}
+
+func (m *SchemaRegMock) getLatestSchemaHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(GetLatestSchema, w) {
+			return
+		}
+		subjectName := pathSubject(req)
+		subject, ok := m.Subjects[subjectName]
+		if !ok || len(subject.SchemaRefs) == 0 {
+			writeSubjectNotFound(w, subjectName)
+			return
+		}
+		writeJson(w, m.toSubjectSchema(subjectName, subject.SchemaRefs[len(subject.SchemaRefs)-1]))
+	}
+}
+
+func (m *SchemaRegMock) listSubjectsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(ListSubjects, w) {
+			return
+		}
+		deleted := req.URL.Query().Get("deleted") == "true"
+		prefix, filtered := req.URL.Query()["subjectPrefix"]
+		matches := func(subjectName string) bool {
+			if !filtered || prefix[0] == schemareg.AllContexts {
+				return true
+			}
+			// plain prefix matches subjects of default context only
+			return strings.HasPrefix(subjectName, prefix[0]) &&
+				(strings.HasPrefix(prefix[0], ":.") || !strings.HasPrefix(subjectName, ":."))
+		}
+		subjects := make([]string, 0, len(m.Subjects))
+		for subjectName := range m.Subjects {
+			if matches(subjectName) {
+				subjects = append(subjects, subjectName)
+			}
+		}
+		if deleted {
+			for subjectName := range m.SoftDeletedSubjects {
+				if _, hardDeleted := m.HardDeletedSubjects[subjectName]; !hardDeleted && matches(subjectName) {
+					subjects = append(subjects, subjectName)
+				}
+			}
+		}
+		sort.Strings(subjects)
+		writeJson(w, subjects)
+	}
+}
+
+func (m *SchemaRegMock) listVersionsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(ListVersions, w) {
+			return
+		}
+		subjectName := pathSubject(req)
+		deleted := req.URL.Query().Get("deleted") == "true"
+		subject := m.findSubject(subjectName, deleted)
+		if subject == nil {
+			writeSubjectNotFound(w, subjectName)
+			return
+		}
+		versions := subject.Versions()
+		if deleted {
+			versions = append(versions, subject.SoftDeletedVersions()...)
+			sort.Ints(versions)
+		}
+		writeJson(w, versions)
+	}
+}
+
+func (m *SchemaRegMock) getSchemaVersionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(GetSchemaVersion, w) {
+			return
+		}
+		pathParts := strings.Split(req.URL.Path, "/")
+		subjectName := pathSubject(req)
+		version, _ := strconv.Atoi(pathParts[4])
+		deleted := req.URL.Query().Get("deleted") == "true"
+		subject := m.findSubject(subjectName, deleted)
+		if subject == nil {
+			writeSubjectNotFound(w, subjectName)
+			return
+		}
+		refs := subject.SchemaRefs
+		if deleted {
+			refs = append(slices.Clone(refs), subject.SoftDeletedRefs...)
+		}
+		for _, ref := range refs {
+			if ref.version == version {
+				writeJson(w, m.toSubjectSchema(subjectName, ref))
+				return
+			}
+		}
+		w.WriteHeader(404)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"error_code":40402,"message":"Version %d not found."}`, version)))
+	}
+}
+
+/*
+findSubject returns active subject, or (if deleted=true) soft-deleted one.
+Returns nil if subject doesn't exist or is hard-deleted
+*/
+func (m *SchemaRegMock) findSubject(subjectName string, deleted bool) *Subject {
+	if subject, ok := m.Subjects[subjectName]; ok {
+		return subject
+	}
+	if _, hardDeleted := m.HardDeletedSubjects[subjectName]; deleted && !hardDeleted {
+		return m.SoftDeletedSubjects[subjectName]
+	}
+	return nil
+}
+
+// findVersion returns reference to (not soft-deleted) version of the subject, nil if it doesn't exist
+func (m *SchemaRegMock) findVersion(subjectName string, version int) *SchemaRef {
+	subject, ok := m.Subjects[subjectName]
+	if !ok {
+		return nil
+	}
+	for _, ref := range subject.SchemaRefs {
+		if ref.version == version {
+			return &ref
+		}
+	}
+	return nil
+}
+
+func (m *SchemaRegMock) getReferencedByHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(GetReferencedBy, w) {
+			return
+		}
+		pathParts := strings.Split(req.URL.Path, "/")
+		subjectName := pathSubject(req)
+		version, _ := strconv.Atoi(pathParts[4])
 		if _, ok := m.Subjects[subjectName]; !ok {
-			m.Subjects[subjectName] = &Subject{
-				CompatibilityMode: v1beta1.BACKWARD,
-				SchemaRefs:        []SchemaRef{},
+			writeSubjectNotFound(w, subjectName)
+			return
+		}
+		referencingIds := m.References[schemareg.SubjectVersion{Subject: subjectName, Version: version}]
+		if referencingIds == nil {
+			referencingIds = []int{}
+		}
+		writeJson(w, referencingIds)
+	}
+}
+
+func (m *SchemaRegMock) getSchemaVersionsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(GetSchemaVersions, w) {
+			return
+		}
+		schemaId, _ := strconv.Atoi(strings.Split(req.URL.Path, "/")[3])
+		context := schemaContext(req.URL.Query().Get("subject"))
+		if _, ok := m.Schemas[schemaId]; !ok || !m.registeredInContext(schemaId, context) {
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(`{"error_code":40403,"message":"Schema not found"}`))
+			return
+		}
+		subjectVersions := []schemareg.SubjectVersion{}
+		for subjectName, subject := range m.Subjects {
+			if schemaContext(subjectName) != context {
+				continue
+			}
+			for _, ref := range subject.SchemaRefs {
+				if ref.schemaId == schemaId {
+					subjectVersions = append(subjectVersions,
+						schemareg.SubjectVersion{Subject: subjectName, Version: ref.version})
+				}
 			}
 		}
+		sort.Slice(subjectVersions, func(i, j int) bool {
+			return subjectVersions[i].Subject < subjectVersions[j].Subject
+		})
+		writeJson(w, subjectVersions)
+	}
+}
 
-		schemaId := m.registerSchema(schema)
-		m.Subjects[subjectName].setSchemaAsCurrentVersion(schemaId)
+func (m *SchemaRegMock) toSubjectSchema(subjectName string, ref SchemaRef) schemareg.SubjectSchema {
+	return schemareg.SubjectSchema{
+		Subject:    subjectName,
+		Id:         ref.schemaId,
+		Version:    ref.version,
+		Schema:     m.Schemas[ref.schemaId],
+		Metadata:   m.Metadata[ref.schemaId],
+		RuleSet:    m.RuleSets[ref.schemaId],
+		References: m.SchemaReferences[ref.schemaId],
+	}
+}
 
-		_, _ = w.Write([]byte(fmt.Sprintf(`{"id": %d}`, schemaId)))
-		w.WriteHeader(200)
+// pathSubject returns (unescaped) subject from request path /{resource}/{subject}/...
+func pathSubject(req *http.Request) string {
+	subject, _ := url.PathUnescape(strings.Split(req.URL.Path, "/")[2])
+	return subject
+}
+
+// schemaContext returns schema context of (qualified) subject, empty for default context
+func schemaContext(subject string) string {
+	if !strings.HasPrefix(subject, ":.") {
+		return ""
 	}
+	context, _, _ := strings.Cut(subject[1:], ":")
+	return context
+}
+
+// registeredInContext tells if schema is registered under (possibly soft-deleted) subject of given schema context
+func (m *SchemaRegMock) registeredInContext(schemaId int, context string) bool {
+	for _, subjects := range []map[string]*Subject{m.Subjects, m.SoftDeletedSubjects} {
+		for subjectName, subject := range subjects {
+			if schemaContext(subjectName) != context {
+				continue
+			}
+			for _, ref := range subject.SchemaRefs {
+				if ref.schemaId == schemaId {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func writeSubjectNotFound(w http.ResponseWriter, subjectName string) {
+	w.WriteHeader(404)
+	_, _ = w.Write([]byte(fmt.Sprintf(`{"error_code":40401,"message":"Subject '%s' not found."}`, subjectName)))
+}
+
+func writeJson(w http.ResponseWriter, body interface{}) {
+	jsonBody, err := json.Marshal(body)
+	Expect(err).Should(Succeed())
+	w.WriteHeader(200)
+	_, _ = w.Write(jsonBody)
 }
 
 func parseSchema(req schemareg.RegisterSchemaReq) (string, error) {
@@ -158,14 +584,77 @@ func readJsonBody[T interface{}](req *http.Request, body *T) *T {
 	return body
 }
 
-func (m *SchemaRegMock) registerSchema(schema string) int {
+// RegisterSchema registers schema under the subject (as if it was registered directly in schema registry)
+func (m *SchemaRegMock) RegisterSchema(subjectName string, schema string) int {
+	return m.Register(subjectName, schemareg.RegisterSchemaReq{Schema: schema})
+}
+
+// Register registers schema (along with its metadata and rule set) under the subject
+func (m *SchemaRegMock) Register(subjectName string, req schemareg.RegisterSchemaReq) int {
+	if softDeleted := m.findSubject(subjectName, true); softDeleted != nil && m.Subjects[subjectName] == nil {
+		// registering under soft-deleted subject brings it back, without reusing soft-deleted versions
+		delete(m.SoftDeletedSubjects, subjectName)
+		softDeleted.SoftDeletedRefs = append(softDeleted.SoftDeletedRefs, softDeleted.SchemaRefs...)
+		softDeleted.SchemaRefs = []SchemaRef{}
+		m.Subjects[subjectName] = softDeleted
+	}
+	if _, ok := m.Subjects[subjectName]; !ok {
+		m.Subjects[subjectName] = &Subject{
+			SchemaRefs: []SchemaRef{},
+		}
+	}
+	schemaId := m.registerSchema(req)
+	m.Subjects[subjectName].setSchemaAsCurrentVersion(schemaId, req.Version)
+	return schemaId
+}
+
+/*
+AddReference registers schema under referencing subject (as if it was registered directly in schema registry)
+and records it as referencing given version of another subject
+*/
+func (m *SchemaRegMock) AddReference(referencingSubject string, schema string, referenced schemareg.SubjectVersion) int {
+	schemaId := m.RegisterSchema(referencingSubject, schema)
+	m.References[referenced] = append(m.References[referenced], schemaId)
+	return schemaId
+}
+
+func (m *SchemaRegMock) storeSchema(schemaId int, req schemareg.RegisterSchemaReq) {
+	m.Schemas[schemaId] = req.Schema
+	if req.Metadata != nil {
+		m.Metadata[schemaId] = req.Metadata
+	}
+	if req.RuleSet != nil {
+		m.RuleSets[schemaId] = req.RuleSet
+	}
+	if len(req.References) > 0 {
+		m.SchemaReferences[schemaId] = req.References
+		for _, ref := range req.References {
+			referenced := schemareg.SubjectVersion{Subject: ref.Subject, Version: ref.Version}
+			m.References[referenced] = append(m.References[referenced], schemaId)
+		}
+	}
+}
+
+func (m *SchemaRegMock) registerSchema(req schemareg.RegisterSchemaReq) int {
+	if req.Id > 0 {
+		// schema with pinned id (registered in IMPORT mode)
+		if _, ok := m.Schemas[req.Id]; !ok {
+			m.storeSchema(req.Id, req)
+			m.nextSchemaId = max(m.nextSchemaId, req.Id+1)
+		}
+		return req.Id
+	}
 	for existingId, existingSchema := range m.Schemas {
-		if existingSchema == schema {
+		// schema id identifies schema along with its metadata, rule set and references
+		if existingSchema == req.Schema &&
+			reflect.DeepEqual(m.Metadata[existingId], req.Metadata) &&
+			reflect.DeepEqual(m.RuleSets[existingId], req.RuleSet) &&
+			reflect.DeepEqual(m.SchemaReferences[existingId], req.References) {
 			return existingId
 		}
 	}
 	schemaId := m.nextSchemaId
-	m.Schemas[schemaId] = schema
+	m.storeSchema(schemaId, req)
 	m.nextSchemaId += 1
 	return schemaId
 }
@@ -175,7 +664,7 @@ func (m *SchemaRegMock) deleteSubjectHandler() http.HandlerFunc {
 		if m.handledByErrorsInjector(DeleteSubject, w) {
 			return
 		}
-		subjectName := strings.Split(req.URL.Path, "/")[2]
+		subjectName := pathSubject(req)
 		queryParams, _ := url.ParseQuery(req.URL.RawQuery)
 		permanent := queryParams.Get("permanent") == "true"
 		versions, err := m.DeleteSubject(subjectName, permanent)
@@ -191,19 +680,45 @@ func (m *SchemaRegMock) deleteSubjectHandler() http.HandlerFunc {
 	}
 }
 
+func (m *SchemaRegMock) deleteSubjectVersionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(DeleteSubjectVersion, w) {
+			return
+		}
+		pathParts := strings.Split(req.URL.Path, "/")
+		subjectName := pathSubject(req)
+		version, _ := strconv.Atoi(pathParts[4])
+		permanent := req.URL.Query().Get("permanent") == "true"
+		if err := m.DeleteSubjectVersion(subjectName, version, permanent); err != nil {
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(err.Error()))
+		} else {
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(strconv.Itoa(version)))
+		}
+	}
+}
+
 func (m *SchemaRegMock) setCompatibilityModeHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		if m.handledByErrorsInjector(SetCompatibilityMode, w) {
 			return
 		}
-		subjectName := strings.Split(req.URL.Path, "/")[2]
-		compatibilityMode := readJsonBody(req, &schemareg.SetCompatibilityModeReq{})
-		if validateCompatibilityMode(compatibilityMode.Compatibility) != nil {
+		subjectName := pathSubject(req)
+		rawBody := readRawBody(req)
+		configReq := &schemareg.SubjectConfigReq{}
+		Expect(json.Unmarshal(rawBody, configReq)).Should(Succeed())
+		if len(configReq.Compatibility) > 0 && validateCompatibilityMode(configReq.Compatibility) != nil {
 			w.WriteHeader(422)
 			_, _ = w.Write([]byte(`{"error_code":42203,"message":"Invalid compatibility level"}`))
+			return
 		}
 		if existingSubject, ok := m.Subjects[subjectName]; ok {
-			existingSubject.CompatibilityMode = compatibilityMode.Compatibility
+			// settings not provided in the request are left untouched
+			if len(configReq.Compatibility) > 0 {
+				existingSubject.CompatibilityMode = configReq.Compatibility
+			}
+			Expect(json.Unmarshal(rawBody, &existingSubject.Config)).Should(Succeed())
 			w.WriteHeader(200)
 			// TODO OK response
 		} else {
@@ -213,6 +728,142 @@ func (m *SchemaRegMock) setCompatibilityModeHandler() http.HandlerFunc {
 	}
 }
 
+func (m *SchemaRegMock) deleteSubjectConfigHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(DeleteSubjectConfig, w) {
+			return
+		}
+		subjectName := pathSubject(req)
+		existingSubject, ok := m.Subjects[subjectName]
+		if !ok || !existingSubject.hasConfig() {
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(fmt.Sprintf(
+				`{"error_code":40408,"message":"Subject '%s' does not have subject-level compatibility configured"}`,
+				subjectName)))
+			return
+		}
+		config := existingSubject.config()
+		existingSubject.CompatibilityMode = ""
+		existingSubject.Config = v1beta1.SubjectConfig{}
+		writeJson(w, config)
+	}
+}
+
+func (m *SchemaRegMock) subjectMode(subjectName string) v1beta1.SubjectMode {
+	if mode, ok := m.Modes[subjectName]; ok {
+		return mode
+	}
+	if len(m.GlobalMode) > 0 {
+		return m.GlobalMode
+	}
+	return v1beta1.ModeReadWrite
+}
+
+func (m *SchemaRegMock) getSubjectModeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(GetSubjectMode, w) {
+			return
+		}
+		subjectName := pathSubject(req)
+		_, ok := m.Modes[subjectName]
+		if !ok && req.URL.Query().Get("defaultToGlobal") != "true" {
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(fmt.Sprintf(
+				`{"error_code":40409,"message":"Subject '%s' does not have subject-level mode configured"}`,
+				subjectName)))
+			return
+		}
+		writeJson(w, schemareg.SubjectModeReq{Mode: m.subjectMode(subjectName)})
+	}
+}
+
+func (m *SchemaRegMock) setSubjectModeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(SetSubjectMode, w) {
+			return
+		}
+		subjectName := pathSubject(req)
+		modeReq := readJsonBody(req, &schemareg.SubjectModeReq{})
+		subject, exists := m.Subjects[subjectName]
+		force := req.URL.Query().Get("force") == "true"
+		if modeReq.Mode == v1beta1.ModeImport && exists && len(subject.SchemaRefs) > 0 && !force {
+			writeOperationNotPermitted(w, fmt.Sprintf("Cannot import since found existing subjects %s", subjectName))
+			return
+		}
+		m.Modes[subjectName] = modeReq.Mode
+		writeJson(w, modeReq)
+	}
+}
+
+func (m *SchemaRegMock) deleteSubjectModeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(DeleteSubjectMode, w) {
+			return
+		}
+		subjectName := pathSubject(req)
+		mode, ok := m.Modes[subjectName]
+		if !ok {
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(fmt.Sprintf(
+				`{"error_code":40409,"message":"Subject '%s' does not have subject-level mode configured"}`,
+				subjectName)))
+			return
+		}
+		delete(m.Modes, subjectName)
+		writeJson(w, schemareg.SubjectModeReq{Mode: mode})
+	}
+}
+
+func writeOperationNotPermitted(w http.ResponseWriter, msg string) {
+	w.WriteHeader(422)
+	_, _ = w.Write([]byte(fmt.Sprintf(`{"error_code":42205,"message":"%s"}`, msg)))
+}
+
+func (m *SchemaRegMock) getSchemaByIdHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(GetSchemaById, w) {
+			return
+		}
+		schemaId, _ := strconv.Atoi(strings.Split(req.URL.Path, "/")[3])
+		schema, ok := m.Schemas[schemaId]
+		if !ok || !m.registeredInContext(schemaId, schemaContext(req.URL.Query().Get("subject"))) {
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(`{"error_code":40403,"message":"Schema not found"}`))
+			return
+		}
+		writeJson(w, schemareg.SubjectSchema{
+			Id:       schemaId,
+			Schema:   schema,
+			Metadata: m.Metadata[schemaId],
+			RuleSet:  m.RuleSets[schemaId],
+		})
+	}
+}
+
+func (m *SchemaRegMock) getCompatibilityModeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if m.handledByErrorsInjector(GetCompatibilityMode, w) {
+			return
+		}
+		subjectName := pathSubject(req)
+		defaultToGlobal := req.URL.Query().Get("defaultToGlobal") == "true"
+		config := schemareg.SubjectConfigRes{}
+		if existingSubject, ok := m.Subjects[subjectName]; ok && existingSubject.hasConfig() {
+			config = existingSubject.config()
+		} else if !defaultToGlobal {
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(fmt.Sprintf(
+				`{"error_code":40408,"message":"Subject '%s' does not have subject-level compatibility configured"}`,
+				subjectName)))
+			return
+		}
+		if len(config.CompatibilityLevel) == 0 && defaultToGlobal {
+			config.CompatibilityLevel = GlobalCompatibilityMode
+		}
+		writeJson(w, config)
+	}
+}
+
 func (m *SchemaRegMock) handledByErrorsInjector(api InjectOnApi, writer http.ResponseWriter) bool {
 	maybeError := m.injectedErrors[api]
 	if maybeError == nil {
@@ -230,6 +881,12 @@ func (m *SchemaRegMock) Clear() {
 	m.logger.Info("Removing previously registered subjects and schemas")
 	m.Subjects = map[string]*Subject{}
 	m.Schemas = map[int]string{}
+	m.Metadata = map[int]*v1beta1.Metadata{}
+	m.RuleSets = map[int]*v1beta1.RuleSet{}
+	m.Modes = map[string]v1beta1.SubjectMode{}
+	m.GlobalMode = ""
+	m.SchemaReferences = map[int][]schemareg.SchemaReference{}
+	m.References = map[schemareg.SubjectVersion][]int{}
 	m.SoftDeletedSubjects = map[string]*Subject{}
 	m.HardDeletedSubjects = map[string]*Subject{}
 	m.injectedErrors = map[InjectOnApi]*InjectedError{}
@@ -239,10 +896,27 @@ type InjectOnApi string
 
 const (
 	RegisterSubject      InjectOnApi = "RegisterSubject"
+	LookupSchema         InjectOnApi = "LookupSchema"
+	GetLatestSchema      InjectOnApi = "GetLatestSchema"
+	ListSubjects         InjectOnApi = "ListSubjects"
 	SetCompatibilityMode InjectOnApi = "SetCompatibilityMode"
+	GetCompatibilityMode InjectOnApi = "GetCompatibilityMode"
+	DeleteSubjectConfig  InjectOnApi = "DeleteSubjectConfig"
+	GetSubjectMode       InjectOnApi = "GetSubjectMode"
+	SetSubjectMode       InjectOnApi = "SetSubjectMode"
+	DeleteSubjectMode    InjectOnApi = "DeleteSubjectMode"
 	DeleteSubject        InjectOnApi = "DeleteSubject"
+	DeleteSubjectVersion InjectOnApi = "DeleteSubjectVersion"
+	ListVersions         InjectOnApi = "ListVersions"
+	GetSchemaVersion     InjectOnApi = "GetSchemaVersion"
+	GetReferencedBy      InjectOnApi = "GetReferencedBy"
+	GetSchemaVersions    InjectOnApi = "GetSchemaVersions"
+	GetSchemaById        InjectOnApi = "GetSchemaById"
 )
 
+// GlobalCompatibilityMode is returned for subjects without subject-level compatibility mode
+const GlobalCompatibilityMode = v1beta1.BACKWARD
+
 type InjectedError struct {
 	OnApi        InjectOnApi
 	StatusCode   int
@@ -276,15 +950,55 @@ func (m *SchemaRegMock) DeleteSubject(subjectName string, permanent bool) ([]int
 	}
 }
 
-func schemaVersions(subject *Subject) []int {
-	keys := make([]int, len(subject.SchemaRefs))
+/*
+DeleteSubjectVersion deletes single version of the subject.
+Subject without active versions is treated as soft-deleted,
+and as hard-deleted once all its versions are permanently deleted
+*/
+func (m *SchemaRegMock) DeleteSubjectVersion(subjectName string, version int, permanent bool) error {
+	subject, active := m.Subjects[subjectName]
+	if !active {
+		subject = m.SoftDeletedSubjects[subjectName]
+	}
+	if subject == nil {
+		return fmt.Errorf(`{"error_code":40401,"message":"Subject '%s' not found."}`, subjectName)
+	}
+	if permanent {
+		for i, ref := range subject.SoftDeletedRefs {
+			if ref.version == version {
+				subject.SoftDeletedRefs = append(subject.SoftDeletedRefs[:i], subject.SoftDeletedRefs[i+1:]...)
+				if len(subject.SchemaRefs) == 0 && len(subject.SoftDeletedRefs) == 0 {
+					m.HardDeletedSubjects[subjectName] = subject
+				}
+				return nil
+			}
+		}
+		if slices.Contains(schemaVersions(subject), version) {
+			return fmt.Errorf(`{"error_code":40407,"message":"Subject '%s' Version %d was not deleted first before being permanently deleted"}`,
+				subjectName, version)
+		}
+	} else if active {
+		for i, ref := range subject.SchemaRefs {
+			if ref.version == version {
+				subject.SchemaRefs = append(subject.SchemaRefs[:i], subject.SchemaRefs[i+1:]...)
+				subject.SoftDeletedRefs = append(subject.SoftDeletedRefs, ref)
+				if len(subject.SchemaRefs) == 0 {
+					delete(m.Subjects, subjectName)
+					m.SoftDeletedSubjects[subjectName] = subject
+				}
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf(`{"error_code":40402,"message":"Version %d not found."}`, version)
+}
 
-	i := 0
-	for k := range subject.SchemaRefs {
-		keys[i] = k
-		i++
+func schemaVersions(subject *Subject) []int {
+	versions := make([]int, len(subject.SchemaRefs))
+	for i, ref := range subject.SchemaRefs {
+		versions[i] = ref.version
 	}
-	return keys
+	return versions
 }
 
 func validateCompatibilityMode(mode v1beta1.CompatibilityMode) error {
@@ -30,6 +30,13 @@ type SrClient struct {
 type RegisterSchemaReq struct {
 	Schema     string               `json:"schema"`
 	SchemaType v1beta1.SchemaFormat `json:"schemaType,omitempty"`
+	Metadata   *v1beta1.Metadata    `json:"metadata,omitempty"`
+	RuleSet    *v1beta1.RuleSet     `json:"ruleSet,omitempty"`
+	// References of schemas registered under other subjects (e.g. imported Protobuf files)
+	References []SchemaReference `json:"references,omitempty"`
+	// Id and Version pin the schema (subject has to be in IMPORT mode)
+	Id      int `json:"id,omitempty"`
+	Version int `json:"version,omitempty"`
 }
 type RegisterSchemaRes struct {
 	Id int `json:"id"`
@@ -39,10 +46,56 @@ type SetCompatibilityModeReq struct {
 	Compatibility v1beta1.CompatibilityMode `json:"compatibility"`
 }
 
+type GetCompatibilityModeRes struct {
+	CompatibilityLevel v1beta1.CompatibilityMode `json:"compatibilityLevel"`
+}
+
+type SubjectModeReq struct {
+	Mode v1beta1.SubjectMode `json:"mode"`
+}
+
+// SubjectConfigReq updates subject level config
+type SubjectConfigReq struct {
+	Compatibility v1beta1.CompatibilityMode `json:"compatibility,omitempty"`
+	v1beta1.SubjectConfig
+}
+
+// SubjectConfigRes is subject level config returned by schema registry
+type SubjectConfigRes struct {
+	CompatibilityLevel v1beta1.CompatibilityMode `json:"compatibilityLevel,omitempty"`
+	v1beta1.SubjectConfig
+}
+
+// SubjectSchema is a schema registered under specific subject and version
+type SubjectSchema struct {
+	Subject    string               `json:"subject"`
+	Id         int                  `json:"id"`
+	Version    int                  `json:"version"`
+	Schema     string               `json:"schema"`
+	SchemaType v1beta1.SchemaFormat `json:"schemaType,omitempty"`
+	Metadata   *v1beta1.Metadata    `json:"metadata,omitempty"`
+	RuleSet    *v1beta1.RuleSet     `json:"ruleSet,omitempty"`
+	References []SchemaReference    `json:"references,omitempty"`
+}
+
+// SchemaReference references schema registered under given subject and version by name used in the schema
+// (e.g. import path of Protobuf file)
+type SchemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// SubjectVersion identifies single version of the subject
+type SubjectVersion struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
 func (c *SrClient) RegisterSchema(subject string, req RegisterSchemaReq) (int, error) {
 	jsonReq, _ := json.Marshal(req)
 	jsonString, err := c.sendHttpRequest(
-		"/subjects/"+subject+"/versions",
+		"/subjects/"+url.PathEscape(subject)+"/versions",
 		"POST",
 		string(jsonReq),
 		map[string]string{})
@@ -58,9 +111,79 @@ func (c *SrClient) RegisterSchema(subject string, req RegisterSchemaReq) (int, e
 	}
 }
 
+// LookupSchema checks if schema is registered under the subject.
+// Returns nil (without error) if either subject or schema doesn't exist
+func (c *SrClient) LookupSchema(subject string, req RegisterSchemaReq) (*SubjectSchema, error) {
+	jsonReq, _ := json.Marshal(req)
+	jsonString, err := c.sendHttpRequest(
+		"/subjects/"+url.PathEscape(subject),
+		"POST",
+		string(jsonReq),
+		map[string]string{})
+	if errors.As(err, &NotFound{}) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	res := &SubjectSchema{}
+	if err := json.Unmarshal([]byte(jsonString), res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetLatestSchema returns the latest (non-deleted) version of the subject.
+// Returns nil (without error) if subject doesn't exist or is soft-deleted
+func (c *SrClient) GetLatestSchema(subject string) (*SubjectSchema, error) {
+	jsonString, err := c.sendHttpRequest(
+		"/subjects/"+url.PathEscape(subject)+"/versions/latest",
+		"GET",
+		"",
+		map[string]string{})
+	if errors.As(err, &NotFound{}) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	res := &SubjectSchema{}
+	if err := json.Unmarshal([]byte(jsonString), res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListSubjects returns names of all subjects in the registry.
+// If deleted=true, soft-deleted subjects are included as well
+// AllContexts is subject prefix matching subjects of all schema registry contexts
+const AllContexts = ":*:"
+
+/*
+ListSubjects lists subjects starting with given prefix: subjects of default context for plain prefix
+(e.g. empty one), subjects of the context for prefix qualified with context (e.g. ":.team-a:")
+and subjects of all contexts for AllContexts
+*/
+func (c *SrClient) ListSubjects(subjectPrefix string, deleted bool) ([]string, error) {
+	jsonString, err := c.sendHttpRequest(
+		"/subjects",
+		"GET",
+		"",
+		map[string]string{
+			"subjectPrefix": subjectPrefix,
+			"deleted":       strconv.FormatBool(deleted),
+		})
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	if err := json.Unmarshal([]byte(jsonString), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (c *SrClient) DeleteSubject(subject string, permanent bool) error {
 	_, err := c.sendHttpRequest(
-		"/subjects/"+subject,
+		"/subjects/"+url.PathEscape(subject),
 		"DELETE",
 		"",
 		map[string]string{
@@ -74,16 +197,272 @@ func (c *SrClient) DeleteSubject(subject string, permanent bool) error {
 	}
 }
 
+// ListVersions returns active versions of the subject. If deleted=true, soft-deleted versions are included as well.
+// Returns nil (without error) if subject doesn't exist (or is soft-deleted, unless deleted=true)
+func (c *SrClient) ListVersions(subject string, deleted bool) ([]int, error) {
+	jsonString, err := c.sendHttpRequest(
+		"/subjects/"+url.PathEscape(subject)+"/versions",
+		"GET",
+		"",
+		map[string]string{
+			"deleted": strconv.FormatBool(deleted),
+		})
+	if errors.As(err, &NotFound{}) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var res []int
+	if err := json.Unmarshal([]byte(jsonString), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetSchemaVersion returns given version of the subject. If deleted=true, soft-deleted version is returned as well.
+// Returns nil (without error) if subject or version doesn't exist
+func (c *SrClient) GetSchemaVersion(subject string, version int, deleted bool) (*SubjectSchema, error) {
+	jsonString, err := c.sendHttpRequest(
+		"/subjects/"+url.PathEscape(subject)+"/versions/"+strconv.Itoa(version),
+		"GET",
+		"",
+		map[string]string{
+			"deleted": strconv.FormatBool(deleted),
+		})
+	if errors.As(err, &NotFound{}) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	res := &SubjectSchema{}
+	if err := json.Unmarshal([]byte(jsonString), res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetReferencedBy returns ids of schemas referencing given version of the subject.
+// Returns nil (without error) if subject or version doesn't exist
+func (c *SrClient) GetReferencedBy(subject string, version int) ([]int, error) {
+	jsonString, err := c.sendHttpRequest(
+		"/subjects/"+url.PathEscape(subject)+"/versions/"+strconv.Itoa(version)+"/referencedby",
+		"GET",
+		"",
+		map[string]string{})
+	if errors.As(err, &NotFound{}) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var res []int
+	if err := json.Unmarshal([]byte(jsonString), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetSchemaVersions returns subject versions the schema (identified by id) is registered under.
+// Schema id is looked up in schema context of the subject (default context if subject is empty).
+// Returns nil (without error) if schema doesn't exist
+func (c *SrClient) GetSchemaVersions(schemaId int, subject string) ([]SubjectVersion, error) {
+	queryParams := map[string]string{}
+	if len(subject) > 0 {
+		queryParams["subject"] = subject
+	}
+	jsonString, err := c.sendHttpRequest(
+		"/schemas/ids/"+strconv.Itoa(schemaId)+"/versions",
+		"GET",
+		"",
+		queryParams)
+	if errors.As(err, &NotFound{}) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var res []SubjectVersion
+	if err := json.Unmarshal([]byte(jsonString), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetSchemaById returns schema registered under given id (subject and version aren't set)
+// in schema context of the subject (default context if subject is empty).
+// Returns nil if there's no such schema
+func (c *SrClient) GetSchemaById(schemaId int, subject string) (*SubjectSchema, error) {
+	queryParams := map[string]string{}
+	if len(subject) > 0 {
+		queryParams["subject"] = subject
+	}
+	jsonString, err := c.sendHttpRequest(
+		"/schemas/ids/"+strconv.Itoa(schemaId),
+		"GET",
+		"",
+		queryParams)
+	if errors.As(err, &NotFound{}) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	res := &SubjectSchema{}
+	if err := json.Unmarshal([]byte(jsonString), res); err != nil {
+		return nil, err
+	}
+	res.Id = schemaId
+	return res, nil
+}
+
+// DeleteSubjectVersion deletes single version of the subject.
+// Permanent deletion requires the version to be soft-deleted first
+func (c *SrClient) DeleteSubjectVersion(subject string, version int, permanent bool) error {
+	_, err := c.sendHttpRequest(
+		"/subjects/"+url.PathEscape(subject)+"/versions/"+strconv.Itoa(version),
+		"DELETE",
+		"",
+		map[string]string{
+			"permanent": strconv.FormatBool(permanent),
+		})
+	if errors.As(err, &NotFound{}) {
+		c.logger.Info("ignoring 404 Not Found error on subject version deletion attempt: " + err.Error())
+		return nil
+	} else {
+		return err
+	}
+}
+
 func (c *SrClient) SetCompatibilityMode(subject string, req SetCompatibilityModeReq) error {
 	jsonReq, _ := json.Marshal(req)
 	_, err := c.sendHttpRequest(
-		"/config/"+subject,
+		"/config/"+url.PathEscape(subject),
 		"PUT",
 		string(jsonReq),
 		map[string]string{})
 	return err
 }
 
+// GetCompatibilityMode returns compatibility mode configured on subject level.
+// Returns empty string if subject doesn't override global compatibility mode
+func (c *SrClient) GetCompatibilityMode(subject string) (v1beta1.CompatibilityMode, error) {
+	jsonString, err := c.sendHttpRequest(
+		"/config/"+url.PathEscape(subject),
+		"GET",
+		"",
+		map[string]string{
+			"defaultToGlobal": "false",
+		})
+	if errors.As(err, &NotFound{}) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	res := GetCompatibilityModeRes{}
+	if err := json.Unmarshal([]byte(jsonString), &res); err != nil {
+		return "", err
+	}
+	return res.CompatibilityLevel, nil
+}
+
+// SetSubjectConfig updates subject level config (settings not provided in the request are left untouched)
+func (c *SrClient) SetSubjectConfig(subject string, req SubjectConfigReq) error {
+	jsonReq, _ := json.Marshal(req)
+	_, err := c.sendHttpRequest(
+		"/config/"+url.PathEscape(subject),
+		"PUT",
+		string(jsonReq),
+		map[string]string{})
+	return err
+}
+
+// GetSubjectConfig returns subject level config.
+// Returns nil if subject doesn't override global config
+func (c *SrClient) GetSubjectConfig(subject string) (*SubjectConfigRes, error) {
+	jsonString, err := c.sendHttpRequest(
+		"/config/"+url.PathEscape(subject),
+		"GET",
+		"",
+		map[string]string{
+			"defaultToGlobal": "false",
+		})
+	if errors.As(err, &NotFound{}) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	res := &SubjectConfigRes{}
+	if err := json.Unmarshal([]byte(jsonString), res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteSubjectConfig removes subject level config, so subject falls back to global config.
+// Subject without subject level config is ignored
+func (c *SrClient) DeleteSubjectConfig(subject string) error {
+	_, err := c.sendHttpRequest(
+		"/config/"+url.PathEscape(subject),
+		"DELETE",
+		"",
+		map[string]string{})
+	if errors.As(err, &NotFound{}) {
+		c.logger.Info("ignoring 404 Not Found error on subject config deletion attempt: " + err.Error())
+		return nil
+	} else {
+		return err
+	}
+}
+
+// GetSubjectMode returns mode configured on subject level.
+// Returns empty string if subject doesn't override global mode
+func (c *SrClient) GetSubjectMode(subject string) (v1beta1.SubjectMode, error) {
+	jsonString, err := c.sendHttpRequest(
+		"/mode/"+url.PathEscape(subject),
+		"GET",
+		"",
+		map[string]string{
+			"defaultToGlobal": "false",
+		})
+	if errors.As(err, &NotFound{}) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	res := SubjectModeReq{}
+	if err := json.Unmarshal([]byte(jsonString), &res); err != nil {
+		return "", err
+	}
+	return res.Mode, nil
+}
+
+// SetSubjectMode updates subject level mode.
+// Force is required to switch non-empty subject to IMPORT mode
+func (c *SrClient) SetSubjectMode(subject string, mode v1beta1.SubjectMode, force bool) error {
+	jsonReq, _ := json.Marshal(SubjectModeReq{Mode: mode})
+	_, err := c.sendHttpRequest(
+		"/mode/"+url.PathEscape(subject),
+		"PUT",
+		string(jsonReq),
+		map[string]string{
+			"force": strconv.FormatBool(force),
+		})
+	return err
+}
+
+// DeleteSubjectMode removes subject level mode, so subject falls back to global mode.
+// Subject without subject level mode is ignored
+func (c *SrClient) DeleteSubjectMode(subject string) error {
+	_, err := c.sendHttpRequest(
+		"/mode/"+url.PathEscape(subject),
+		"DELETE",
+		"",
+		map[string]string{})
+	if errors.As(err, &NotFound{}) {
+		c.logger.Info("ignoring 404 Not Found error on subject mode deletion attempt: " + err.Error())
+		return nil
+	} else {
+		return err
+	}
+}
+
 func (c *SrClient) sendHttpRequest(
 	uri string, httpMethod string, payload string, queryParams map[string]string) (string, error) {
 
@@ -166,9 +545,26 @@ func (e NotFound) Error() string {
 	return e.msg
 }
 
+// OperationNotPermitted is returned if operation is rejected by schema registry (e.g. due to read-only mode)
+type OperationNotPermitted struct {
+	msg string
+}
+
+func (e OperationNotPermitted) Error() string {
+	return e.msg
+}
+
+const operationNotPermittedErrorCode = 42205
+
 func toError(status int, s string) error {
+	errorRes := struct {
+		ErrorCode int `json:"error_code"`
+	}{}
+	_ = json.Unmarshal([]byte(s), &errorRes)
 	if status == 404 {
 		return NotFound{s}
+	} else if status == 422 && errorRes.ErrorCode == operationNotPermittedErrorCode {
+		return OperationNotPermitted{fmt.Sprintf("%d, %s", status, s)}
 	} else {
 		return fmt.Errorf("%d, %s", status, s)
 	}
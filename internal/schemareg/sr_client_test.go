@@ -1,10 +1,13 @@
 package schemareg
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"regexp"
 	"strings"
 
 	"incubly.oss/kafka-schema-operator/api/v1beta1"
@@ -65,10 +68,33 @@ var _ = Describe("SrClient", func() {
 	})
 	Context("When using client", func() {
 		var collectedRequests []*http.Request
+		var collectedBodies []string
 		schemaRegMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			collectedRequests = append(collectedRequests, r.Clone(r.Context()))
+			body, _ := io.ReadAll(r.Body)
+			collectedBodies = append(collectedBodies, string(body))
 			if isRegisterSchemaRequest(r) {
 				_, _ = w.Write([]byte(`{"id": -1234}`))
+			} else if isSubjectSchemaRequest(r) {
+				_, _ = w.Write([]byte(`{"subject":"mysubject","id":-1234,"version":3,"schema":"\"string\""}`))
+			} else if r.Method == "GET" && r.URL.Path == "/subjects" {
+				_, _ = w.Write([]byte(`["foo","bar"]`))
+			} else if r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/mode/") {
+				_, _ = w.Write([]byte(`{"mode":"READONLY"}`))
+			} else if r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/mode/") {
+				w.WriteHeader(422)
+				_, _ = w.Write([]byte(`{"error_code":42205,"message":"Subject is not empty"}`))
+				return
+			} else if r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/config/") {
+				_, _ = w.Write([]byte(`{"compatibilityLevel":"FULL","alias":"other","defaultMetadata":{"properties":{"owner":"team"}}}`))
+			} else if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/referencedby") {
+				_, _ = w.Write([]byte(`[101,102]`))
+			} else if r.Method == "GET" && regexp.MustCompile(`^/schemas/ids/[0-9]+$`).MatchString(r.URL.Path) {
+				_, _ = w.Write([]byte(`{"schema":"\"string\""}`))
+			} else if r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/schemas/ids/") {
+				_, _ = w.Write([]byte(`[{"subject":"foo","version":1},{"subject":"bar","version":2}]`))
+			} else if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/versions") {
+				_, _ = w.Write([]byte(`[1,2,3]`))
 			}
 			w.WriteHeader(200)
 		}))
@@ -83,6 +109,7 @@ var _ = Describe("SrClient", func() {
 		}
 		BeforeEach(func() {
 			collectedRequests = []*http.Request{}
+			collectedBodies = []string{}
 		})
 
 		It("Should register schema under subject", func() {
@@ -123,6 +150,106 @@ var _ = Describe("SrClient", func() {
 			//Expect(actualReq.Body).Should(BeNil())
 			Expect(actualReq.Header).Should(HaveKeyWithValue("Content-Type", []string{"application/vnd.schemaregistry.v1+json"}))
 		})
+		It("Should permanently delete subject version", func() {
+			Expect(clientUnderTest.DeleteSubjectVersion("mysubject", 3, true)).Should(Succeed())
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/subjects/mysubject/versions/3"))
+			Expect(actualReq.URL.Query().Get("permanent")).Should(Equal("true"))
+			Expect(actualReq.Method).Should(Equal("DELETE"))
+		})
+		It("Should list subject versions", func() {
+			res, err := clientUnderTest.ListVersions("mysubject", true)
+			Expect(err).Should(Succeed())
+			Expect(res).Should(Equal([]int{1, 2, 3}))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/subjects/mysubject/versions"))
+			Expect(actualReq.URL.Query().Get("deleted")).Should(Equal("true"))
+			Expect(actualReq.Method).Should(Equal("GET"))
+		})
+		It("Should get (soft-deleted) subject version", func() {
+			res, err := clientUnderTest.GetSchemaVersion("mysubject", 3, true)
+			Expect(err).Should(Succeed())
+			Expect(res.Version).Should(Equal(3))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/subjects/mysubject/versions/3"))
+			Expect(actualReq.URL.Query().Get("deleted")).Should(Equal("true"))
+			Expect(actualReq.Method).Should(Equal("GET"))
+		})
+		It("Should get schemas referencing subject version", func() {
+			res, err := clientUnderTest.GetReferencedBy("mysubject", 3)
+			Expect(err).Should(Succeed())
+			Expect(res).Should(Equal([]int{101, 102}))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/subjects/mysubject/versions/3/referencedby"))
+			Expect(actualReq.Method).Should(Equal("GET"))
+		})
+		It("Should get subject versions of schema", func() {
+			res, err := clientUnderTest.GetSchemaVersions(101, ":.team-a:mysubject")
+			Expect(err).Should(Succeed())
+			Expect(res).Should(Equal([]SubjectVersion{{"foo", 1}, {"bar", 2}}))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/schemas/ids/101/versions"))
+			Expect(actualReq.URL.Query().Get("subject")).Should(Equal(":.team-a:mysubject"))
+			Expect(actualReq.Method).Should(Equal("GET"))
+		})
+		It("Should get schema by id", func() {
+			res, err := clientUnderTest.GetSchemaById(101, "")
+			Expect(err).Should(Succeed())
+			Expect(res.Id).Should(Equal(101))
+			Expect(res.Schema).Should(Equal(`"string"`))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/schemas/ids/101"))
+			Expect(actualReq.URL.Query()).ShouldNot(HaveKey("subject"))
+			Expect(actualReq.Method).Should(Equal("GET"))
+		})
+		It("Should get schema by id in schema context of subject", func() {
+			_, err := clientUnderTest.GetSchemaById(101, ":.team-a:mysubject")
+			Expect(err).Should(Succeed())
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/schemas/ids/101"))
+			Expect(actualReq.URL.Query().Get("subject")).Should(Equal(":.team-a:mysubject"))
+		})
+		It("Should register schema with pinned id and version", func() {
+			Expect(clientUnderTest.RegisterSchema("mysubject", RegisterSchemaReq{
+				Schema:     `"string"`,
+				SchemaType: v1beta1.AVRO,
+				Id:         101,
+				Version:    3,
+			})).Should(Equal(-1234))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			Expect(collectedBodies[0]).Should(MatchJSON(
+				`{"schema":"\"string\"","schemaType":"AVRO","id":101,"version":3}`))
+		})
+		It("Should register schema with references under escaped subject", func() {
+			Expect(clientUnderTest.RegisterSchema("common/order.proto", RegisterSchemaReq{
+				Schema:     `import "common/money.proto";`,
+				SchemaType: v1beta1.PROTOBUF,
+				References: []SchemaReference{{Name: "common/money.proto", Subject: "common/money.proto", Version: 2}},
+			})).Should(Equal(-1234))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.RequestURI).Should(Equal("/subjects/common%2Forder.proto/versions"))
+			Expect(collectedBodies[0]).Should(MatchJSON(`{
+				"schema":"import \"common/money.proto\";",
+				"schemaType":"PROTOBUF",
+				"references":[{"name":"common/money.proto","subject":"common/money.proto","version":2}]
+			}`))
+		})
 		It("Should set compatibility mode", func() {
 			Expect(
 				clientUnderTest.SetCompatibilityMode(
@@ -140,6 +267,124 @@ var _ = Describe("SrClient", func() {
 			//Expect(io.ReadAll(actualReq.Body)).Should(Equal("BACKWARD"))
 			Expect(actualReq.Header).Should(HaveKeyWithValue("Content-Type", []string{"application/vnd.schemaregistry.v1+json"}))
 		})
+		It("Should get latest schema of subject", func() {
+			res, err := clientUnderTest.GetLatestSchema("mysubject")
+			Expect(err).Should(Succeed())
+			Expect(res.Id).Should(Equal(-1234))
+			Expect(res.Version).Should(Equal(3))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/subjects/mysubject/versions/latest"))
+			Expect(actualReq.Method).Should(Equal("GET"))
+		})
+		It("Should look up schema under subject", func() {
+			res, err := clientUnderTest.LookupSchema("mysubject", RegisterSchemaReq{
+				Schema:     `"string"`,
+				SchemaType: v1beta1.AVRO,
+			})
+			Expect(err).Should(Succeed())
+			Expect(res.Version).Should(Equal(3))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/subjects/mysubject"))
+			Expect(actualReq.Method).Should(Equal("POST"))
+		})
+		It("Should list subjects including soft-deleted", func() {
+			res, err := clientUnderTest.ListSubjects(AllContexts, true)
+			Expect(err).Should(Succeed())
+			Expect(res).Should(Equal([]string{"foo", "bar"}))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/subjects"))
+			Expect(actualReq.URL.Query().Get("deleted")).Should(Equal("true"))
+			Expect(actualReq.URL.Query().Get("subjectPrefix")).Should(Equal(":*:"))
+		})
+		It("Should list subjects of default context with empty prefix", func() {
+			_, err := clientUnderTest.ListSubjects("", false)
+			Expect(err).Should(Succeed())
+
+			Expect(collectedRequests).To(HaveLen(1))
+			query := collectedRequests[0].URL.Query()
+			Expect(query).Should(HaveKey("subjectPrefix"))
+			Expect(query.Get("subjectPrefix")).Should(BeEmpty())
+		})
+		It("Should get subject-level compatibility mode", func() {
+			res, err := clientUnderTest.GetCompatibilityMode("mysubject")
+			Expect(err).Should(Succeed())
+			Expect(res).Should(Equal(v1beta1.FULL))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/config/mysubject"))
+			Expect(actualReq.URL.Query().Get("defaultToGlobal")).Should(Equal("false"))
+			Expect(actualReq.Method).Should(Equal("GET"))
+		})
+		It("Should get subject-level config", func() {
+			res, err := clientUnderTest.GetSubjectConfig("mysubject")
+			Expect(err).Should(Succeed())
+			Expect(res.CompatibilityLevel).Should(Equal(v1beta1.FULL))
+			Expect(res.Alias).Should(Equal("other"))
+			Expect(res.DefaultMetadata.Properties).Should(HaveKeyWithValue("owner", "team"))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/config/mysubject"))
+			Expect(actualReq.URL.Query().Get("defaultToGlobal")).Should(Equal("false"))
+			Expect(actualReq.Method).Should(Equal("GET"))
+		})
+		It("Should set subject-level config", func() {
+			Expect(clientUnderTest.SetSubjectConfig("mysubject", SubjectConfigReq{
+				Compatibility: v1beta1.FULL,
+				SubjectConfig: v1beta1.SubjectConfig{CompatibilityGroup: "app.version"},
+			})).Should(Succeed())
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/config/mysubject"))
+			Expect(actualReq.Method).Should(Equal("PUT"))
+			Expect(collectedBodies[0]).Should(MatchJSON(
+				`{"compatibility":"FULL","compatibilityGroup":"app.version"}`))
+		})
+		It("Should keep subject qualified with schema context in request path", func() {
+			Expect(clientUnderTest.DeleteSubjectConfig(":.team-a:mysubject")).Should(Succeed())
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/config/:.team-a:mysubject"))
+			Expect(actualReq.RequestURI).Should(HavePrefix("/config/:.team-a:mysubject"))
+		})
+		It("Should get subject-level mode", func() {
+			res, err := clientUnderTest.GetSubjectMode("mysubject")
+			Expect(err).Should(Succeed())
+			Expect(res).Should(Equal(v1beta1.ModeReadOnly))
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/mode/mysubject"))
+			Expect(actualReq.URL.Query().Get("defaultToGlobal")).Should(Equal("false"))
+		})
+		It("Should report operation rejected by schema registry as not permitted", func() {
+			err := clientUnderTest.SetSubjectMode("mysubject", v1beta1.ModeImport, false)
+			Expect(errors.As(err, &OperationNotPermitted{})).Should(BeTrue())
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/mode/mysubject"))
+			Expect(actualReq.URL.Query().Get("force")).Should(Equal("false"))
+			Expect(actualReq.Method).Should(Equal("PUT"))
+			Expect(collectedBodies[0]).Should(MatchJSON(`{"mode":"IMPORT"}`))
+		})
+		It("Should delete subject-level config", func() {
+			Expect(clientUnderTest.DeleteSubjectConfig("mysubject")).Should(Succeed())
+
+			Expect(collectedRequests).To(HaveLen(1))
+			actualReq := collectedRequests[0]
+			Expect(actualReq.URL.Path).Should(Equal("/config/mysubject"))
+			Expect(actualReq.Method).Should(Equal("DELETE"))
+		})
 		//It("Should not send basic auth if client has no auth", func() {
 		//	Expect(
 		//		clientUnderTest.SetCompatibilityMode(
@@ -172,3 +417,9 @@ var _ = Describe("SrClient", func() {
 func isRegisterSchemaRequest(r *http.Request) bool {
 	return r.Method == "POST" && strings.Contains(r.URL.Path, "/versions")
 }
+
+func isSubjectSchemaRequest(r *http.Request) bool {
+	return (r.Method == "POST" && strings.HasPrefix(r.URL.Path, "/subjects/")) ||
+		(r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/versions/latest")) ||
+		(r.Method == "GET" && regexp.MustCompile(`/versions/[0-9]+$`).MatchString(r.URL.Path))
+}